@@ -1,9 +1,16 @@
 package cmd
 
 import (
+	"encoding/json"
+	"fmt"
 	"os"
+	"slices"
+	"strings"
 
 	"github.com/kimond/godio/pkg/godio"
+	"github.com/kimond/godio/pkg/godio/guitar"
+	"github.com/kimond/godio/pkg/godio/reharm"
+	"github.com/samber/lo"
 	"github.com/spf13/cobra"
 )
 
@@ -12,12 +19,22 @@ var rootCmd = &cobra.Command{
 	Use:   "godio",
 	Short: "Sound generation library",
 	Long:  `godio is a library for generating sound. It is a work in progress.`,
+	// Errors such as invalid chord symbols are not usage errors
+	SilenceUsage:      true,
+	PersistentPreRunE: loadChordDefinitions,
 }
 
 func Execute() {
 	rootCmd.AddCommand(noteCmd)
 	rootCmd.AddCommand(chordCmd)
 	rootCmd.AddCommand(sequenceCmd)
+	rootCmd.AddCommand(identifyCmd)
+	rootCmd.AddCommand(transposeCmd)
+	rootCmd.AddCommand(guitarCmd)
+	rootCmd.AddCommand(showCmd)
+	rootCmd.AddCommand(scaleCmd)
+	rootCmd.AddCommand(analyzeCmd)
+	rootCmd.AddCommand(reharmCmd)
 	err := rootCmd.Execute()
 	if err != nil {
 		os.Exit(1)
@@ -26,11 +43,53 @@ func Execute() {
 
 func init() {
 	rootCmd.Flags().BoolP("toggle", "t", false, "Help message for toggle")
+	rootCmd.PersistentFlags().String("tuning", "equal", "Tuning (equal, just, pythagorean, meantone, werckmeister, vallotti) or path to a Scala .scl file")
+	rootCmd.PersistentFlags().String("kbm", "", "Path to a Scala .kbm keyboard mapping for a .scl tuning")
+	rootCmd.PersistentFlags().String("tonic", "C", "Tonic of just, Pythagorean, meantone, well tempered and Scala tunings")
+	rootCmd.PersistentFlags().Float64("reference", 440, "Frequency of A4 in Hz, e.g. 415 for baroque pitch")
+	rootCmd.PersistentFlags().Int("sample-rate", 44100, "Sample rate of WAV files in Hz, from 22050 to 192000")
+	rootCmd.PersistentFlags().String("sample-format", string(godio.SampleFormatInt16), "Sample format of WAV files (int16, int24, int32, float32)")
+	rootCmd.PersistentFlags().Int("channels", 1, "Number of channels of WAV files")
+	rootCmd.PersistentFlags().String("chords", "", "Path to a JSON file of chord qualities and aliases understood in chord symbols")
 
 	addCommonFlags(noteCmd)
 	addCommonFlags(chordCmd)
 	addCommonFlags(sequenceCmd)
 	sequenceCmd.Flags().Bool("v2", false, "Use voicing v2")
+	addVoicingFlags(chordCmd)
+	addVoicingFlags(sequenceCmd)
+	sequenceCmd.Flags().Bool("voice-leading", false, "Voice each chord with the least movement from the previous one")
+	sequenceCmd.Flags().StringSlice("melody", nil, "Melody note kept on top of each chord, e.g. E5,D5,C5")
+	chordCmd.Flags().String("top", "", "Melody note kept on top of the chord, e.g. D5")
+	sequenceCmd.Flags().StringP("key", "k", "", "Key of Roman numeral (ii7 V7 Imaj7) or Nashville number (2m7 5 1) chords, e.g. C or F#m")
+	chordCmd.Flags().Bool("trace", false, "Print the voicing rules that remove or add chord tones")
+	identifyCmd.Flags().IntP("limit", "l", 5, "Maximum number of candidates to print")
+	transposeCmd.Flags().IntP("semitones", "s", 0, "Number of semitones to transpose by, negative to go down")
+	transposeCmd.Flags().String("spelling", "key", "Spelling of the transposed notes (key, sharps, flats)")
+	addCommonFlags(scaleCmd)
+	scaleCmd.Flags().Int("octave", 4, "Octave of the tonic")
+	scaleCmd.Flags().Bool("descend", true, "Play the scale back down after going up")
+	analyzeCmd.Flags().StringP("key", "k", "", "Key to analyze the chords in, estimated by default. Roman numeral and Nashville number chords need it")
+	analyzeCmd.Flags().StringP("format", "f", "table", "Output format (table, json)")
+	reharmCmd.Flags().StringP("key", "k", "", "Key of Roman numeral (ii7 V7 Imaj7) or Nashville number (2m7 5 1) chords, e.g. C or F#m")
+	reharmCmd.Flags().StringSlice("transforms", lo.Map(reharm.Transforms, func(transform reharm.Transform, _ int) string { return transform.Name }), "Transforms to apply, in order")
+	reharmCmd.Flags().Float64P("probability", "p", 0.5, "Chance of applying a transform at each chord it applies to")
+	reharmCmd.Flags().Int64("seed", 1, "Seed of the random choices, the same seed giving the same progression")
+	reharmCmd.Flags().Float64P("duration", "d", 1, "Duration of each chord in seconds")
+	reharmCmd.Flags().StringP("waveform", "w", string(godio.WaveformTriangle), "Waveform to use (Sine, Square, Sawtooth, Triangle)")
+	reharmCmd.Flags().StringP("output", "o", "", "Output file name of the new progression, none by default")
+	addVoicingStyleFlag(showCmd)
+	showCmd.Flags().Bool("v2", false, "Show voicing v2")
+	showCmd.Flags().StringP("format", "f", "ascii", "Output format (ascii, svg)")
+	showCmd.Flags().String("instrument", "piano", "Instrument of the diagram (piano, guitar)")
+	showCmd.Flags().String("strings", "standard", "Guitar tuning (standard, dropd, dadgad) or the open strings, e.g. D2,G2,D3,G3,B3,D4")
+	showCmd.Flags().StringP("output", "o", "", "Output file name, the standard output by default")
+	guitarCmd.Flags().String("strings", "standard", "Guitar tuning (standard, dropd, dadgad) or the open strings, e.g. D2,G2,D3,G3,B3,D4")
+	guitarCmd.Flags().IntP("limit", "l", 3, "Maximum number of fingerings to print")
+	guitarCmd.Flags().Int("span", 4, "Maximum number of frets covered by a fingering")
+	guitarCmd.Flags().Float64P("duration", "d", 2, "Duration in seconds")
+	guitarCmd.Flags().StringP("waveform", "w", string(godio.WaveformTriangle), "Waveform to use (Sine, Square, Sawtooth, Triangle)")
+	guitarCmd.Flags().StringP("output", "o", "", "Output file name of the easiest fingering strummed, none by default")
 }
 
 func addCommonFlags(cmd *cobra.Command) {
@@ -39,13 +98,135 @@ func addCommonFlags(cmd *cobra.Command) {
 	cmd.Flags().StringP("output", "o", "note.wav", "Output file name")
 }
 
+func addVoicingFlags(cmd *cobra.Command) {
+	addVoicingStyleFlag(cmd)
+	cmd.Flags().String("rules", "default", "Voicing rule set (default, none) or path to a JSON rule set")
+	cmd.Flags().String("instrument", "", "Keep the voicing in the range of an instrument (piano, guitar, vibraphone, brass)")
+}
+
+// getConstraints returns the voicing constraints of the instrument flag, none when it is empty
+func getConstraints(cmd *cobra.Command) (godio.VoicingConstraints, error) {
+	name, err := cmd.Flags().GetString("instrument")
+	if err != nil {
+		panic(err)
+	}
+	if name == "" {
+		return godio.VoicingConstraints{}, nil
+	}
+	return godio.ParseInstrumentConstraints(name)
+}
+
+func addVoicingStyleFlag(cmd *cobra.Command) {
+	cmd.Flags().String("voicing", "", "Voicing style (close, drop2, drop3, drop2and4, shell, rootless-a, rootless-b, quartal, spread)")
+}
+
+// newSoundBuffer returns a sound buffer with the sample rate, format and channels of the flags
+func newSoundBuffer(cmd *cobra.Command) (*godio.SoundBuffer, error) {
+	sampleRate, err := cmd.Flags().GetInt("sample-rate")
+	if err != nil {
+		panic(err)
+	}
+	format, err := cmd.Flags().GetString("sample-format")
+	if err != nil {
+		panic(err)
+	}
+	channels, err := cmd.Flags().GetInt("channels")
+	if err != nil {
+		panic(err)
+	}
+	return godio.NewSoundBufferE(
+		godio.WithSampleRate(sampleRate),
+		godio.WithSampleFormat(godio.SampleFormat(format)),
+		godio.WithChannels(channels),
+	)
+}
+
+// loadChordDefinitions registers the chord qualities of the file given by the chords flag
+func loadChordDefinitions(cmd *cobra.Command, args []string) error {
+	path, err := cmd.Flags().GetString("chords")
+	if err != nil {
+		panic(err)
+	}
+	if path == "" {
+		return nil
+	}
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	return godio.DefaultChordRegistry.Load(file)
+}
+
+// getVoicingRuleSet returns the voicing rule set selected by the rules flag
+func getVoicingRuleSet(cmd *cobra.Command) (*godio.VoicingRuleSet, error) {
+	name, err := cmd.Flags().GetString("rules")
+	if err != nil {
+		panic(err)
+	}
+	if !strings.HasSuffix(name, ".json") {
+		return godio.VoicingRuleSetByName(name)
+	}
+	file, err := os.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	return godio.LoadVoicingRuleSet(file)
+}
+
+// getVoicingStyle returns the voicing style selected by the voicing flag, empty for the default voicing
+func getVoicingStyle(cmd *cobra.Command) (godio.VoicingStyle, error) {
+	name, err := cmd.Flags().GetString("voicing")
+	if err != nil {
+		panic(err)
+	}
+	if name == "" {
+		return "", nil
+	}
+	return godio.ParseVoicingStyle(name)
+}
+
+// getTuning returns the tuning selected by the tuning flags
+func getTuning(cmd *cobra.Command) (godio.Tuning, error) {
+	name, err := cmd.Flags().GetString("tuning")
+	if err != nil {
+		panic(err)
+	}
+	kbm, err := cmd.Flags().GetString("kbm")
+	if err != nil {
+		panic(err)
+	}
+	tonicName, err := cmd.Flags().GetString("tonic")
+	if err != nil {
+		panic(err)
+	}
+	reference, err := cmd.Flags().GetFloat64("reference")
+	if err != nil {
+		panic(err)
+	}
+
+	tonic, err := godio.ParseNoteE(tonicName)
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case name == "equal":
+		return godio.EqualTemperament{Reference: reference}, nil
+	case strings.HasSuffix(name, ".scl"):
+		return godio.LoadScala(name, kbm, tonic.PitchClass(), reference)
+	default:
+		return godio.NewTemperament(name, tonic.PitchClass(), reference)
+	}
+}
+
 var noteCmd = &cobra.Command{
 	Use:        "note [frequency]",
 	Short:      "Generate a note",
 	Long:       `Generate a note with a given frequency.`,
 	Args:       cobra.ExactArgs(1),
 	ArgAliases: []string{"frequency"},
-	Run: func(cmd *cobra.Command, args []string) {
+	RunE: func(cmd *cobra.Command, args []string) error {
 		frequency := args[0]
 		duration, err := cmd.Flags().GetFloat64("duration")
 		if err != nil {
@@ -60,8 +241,21 @@ var noteCmd = &cobra.Command{
 			panic(err)
 		}
 
-		sb := godio.NewSoundBuffer()
-		sb.AppendNote(godio.NoteFrequencies[frequency], duration, godio.Waveform(waveform))
+		pitch, err := godio.ParsePitchE(frequency)
+		if err != nil {
+			return err
+		}
+
+		tuning, err := getTuning(cmd)
+		if err != nil {
+			return err
+		}
+
+		sb, err := newSoundBuffer(cmd)
+		if err != nil {
+			return err
+		}
+		sb.AppendNote(tuning.Frequency(pitch.MIDI()), duration, godio.Waveform(waveform))
 
 		wavFile, err := os.Create(output)
 		if err != nil {
@@ -71,6 +265,7 @@ var noteCmd = &cobra.Command{
 		if err := sb.Write(wavFile); err != nil {
 			panic(err)
 		}
+		return nil
 	},
 }
 
@@ -80,7 +275,7 @@ var chordCmd = &cobra.Command{
 	Long:       `Generate a chord.`,
 	Args:       cobra.ExactArgs(1),
 	ArgAliases: []string{"chord"},
-	Run: func(cmd *cobra.Command, args []string) {
+	RunE: func(cmd *cobra.Command, args []string) error {
 		chordString := args[0]
 
 		duration, err := cmd.Flags().GetFloat64("duration")
@@ -95,9 +290,55 @@ var chordCmd = &cobra.Command{
 		if err != nil {
 			panic(err)
 		}
+		trace, err := cmd.Flags().GetBool("trace")
+		if err != nil {
+			panic(err)
+		}
+		top, err := cmd.Flags().GetString("top")
+		if err != nil {
+			panic(err)
+		}
 
-		chord := godio.ParseChord(chordString)
-		sb := godio.NewSoundBuffer()
+		chord, err := godio.ParseChordE(chordString)
+		if err != nil {
+			return err
+		}
+		chord.Tuning, err = getTuning(cmd)
+		if err != nil {
+			return err
+		}
+		chord.Style, err = getVoicingStyle(cmd)
+		if err != nil {
+			return err
+		}
+		rules, err := getVoicingRuleSet(cmd)
+		if err != nil {
+			return err
+		}
+		chord = chord.WithVoicingRules(rules)
+		chord.Constraints, err = getConstraints(cmd)
+		if err != nil {
+			return err
+		}
+		if top != "" {
+			melody, err := godio.ParsePitchE(top)
+			if err != nil {
+				return err
+			}
+			chord, err = chord.WithTopNoteE(melody)
+			if err != nil {
+				return err
+			}
+		}
+		if trace {
+			for _, step := range chord.TraceVoicingRules() {
+				fmt.Fprintf(cmd.OutOrStdout(), "%s: removed %v, added %v\n", step.Rule, step.Removed, step.Added)
+			}
+		}
+		sb, err := newSoundBuffer(cmd)
+		if err != nil {
+			return err
+		}
 		sb.AppendChord(chord.GetFrequencies(), duration, godio.Waveform(waveform))
 		sb.ApplyADSR(godio.ADSREnvelope{
 			Attack:  10,
@@ -114,6 +355,7 @@ var chordCmd = &cobra.Command{
 		if err := sb.Write(wavFile); err != nil {
 			panic(err)
 		}
+		return nil
 	},
 }
 
@@ -121,7 +363,7 @@ var sequenceCmd = &cobra.Command{
 	Use:   "sequence",
 	Short: "Generate a sequence of chords",
 	Long:  `Generate a sequence of chords.`,
-	Run: func(cmd *cobra.Command, args []string) {
+	RunE: func(cmd *cobra.Command, args []string) error {
 		duration, err := cmd.Flags().GetFloat64("duration")
 		if err != nil {
 			panic(err)
@@ -138,14 +380,85 @@ var sequenceCmd = &cobra.Command{
 		if err != nil {
 			panic(err)
 		}
-		chords := args
+		voiceLeading, err := cmd.Flags().GetBool("voice-leading")
+		if err != nil {
+			panic(err)
+		}
+		keyName, err := cmd.Flags().GetString("key")
+		if err != nil {
+			panic(err)
+		}
+		var key *godio.Key
+		if keyName != "" {
+			parsedKey, err := godio.ParseKey(keyName)
+			if err != nil {
+				return err
+			}
+			key = &parsedKey
+		}
+		chords, err := parseChords(args, key)
+		if err != nil {
+			return err
+		}
+		melody, err := cmd.Flags().GetStringSlice("melody")
+		if err != nil {
+			panic(err)
+		}
+		if len(melody) > len(chords) {
+			return fmt.Errorf("got %d melody notes for %d chords", len(melody), len(chords))
+		}
 
-		sb := godio.NewSoundBuffer()
-		for _, chordStr := range chords {
-			chord := godio.ParseChord(chordStr)
-			if v2 {
+		tuning, err := getTuning(cmd)
+		if err != nil {
+			return err
+		}
+
+		style, err := getVoicingStyle(cmd)
+		if err != nil {
+			return err
+		}
+		rules, err := getVoicingRuleSet(cmd)
+		if err != nil {
+			return err
+		}
+		for i, chord := range chords {
+			chords[i] = chord.WithVoicingRules(rules)
+		}
+		constraints, err := getConstraints(cmd)
+		if err != nil {
+			return err
+		}
+		for i, chord := range chords {
+			chord.Constraints = constraints
+			if i < len(melody) {
+				note, err := godio.ParsePitchE(melody[i])
+				if err != nil {
+					return err
+				}
+				chords[i], err = chord.WithTopNoteE(note)
+				if err != nil {
+					return err
+				}
+			}
+		}
+		voicer := godio.NewVoicer()
+		voicer.Style = style
+		voicer.Constraints = constraints
+		voicings := voicer.Voice(chords)
+
+		sb, err := newSoundBuffer(cmd)
+		if err != nil {
+			return err
+		}
+		for i, chord := range chords {
+			chord.Tuning = tuning
+			chord.Style = style
+			switch {
+			case v2:
 				sb.AppendChord(chord.GetFrequenciesV2(), duration, godio.Waveform(waveform))
-			} else {
+			case voiceLeading:
+				sb.AppendChord(voicings[i].Frequencies(tuning), duration, godio.Waveform(waveform))
+			default:
 				sb.AppendChord(chord.GetFrequencies(), duration, godio.Waveform(waveform))
 			}
 		}
@@ -164,5 +477,503 @@ var sequenceCmd = &cobra.Command{
 		if err := sb.Write(wavFile); err != nil {
 			panic(err)
 		}
+		return nil
+	},
+}
+
+// parseChords parses every chord symbol before anything is rendered so that
+// an invalid symbol is reported instead of producing silence. Roman numerals
+// and Nashville numbers are accepted when a key is given.
+func parseChords(symbols []string, key *godio.Key) ([]*godio.Chord, error) {
+	chords := make([]*godio.Chord, 0, len(symbols))
+	for _, symbol := range symbols {
+		parse := godio.ParseChordE
+		if key != nil {
+			parse = key.ParseChord
+		}
+		chord, err := parse(symbol)
+		if err != nil {
+			return nil, err
+		}
+		chords = append(chords, chord)
+	}
+	return chords, nil
+}
+
+// inversionNames describes the ChordCandidate inversions
+var inversionNames = map[int]string{
+	-1: "slash chord",
+	0:  "root position",
+	1:  "first inversion",
+	2:  "second inversion",
+	3:  "third inversion",
+}
+
+var identifyCmd = &cobra.Command{
+	Use:   "identify [notes...]",
+	Short: "Identify a chord from notes",
+	Long:  `Identify the chord formed by notes with octaves such as "E3 G3 C4". The lowest note is the bass.`,
+	Args:  cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		limit, err := cmd.Flags().GetInt("limit")
+		if err != nil {
+			panic(err)
+		}
+
+		pitches := []int{}
+		for _, note := range args {
+			pitch, err := godio.ParsePitchE(note)
+			if err != nil {
+				return err
+			}
+			pitches = append(pitches, pitch.MIDI())
+		}
+
+		candidates := godio.IdentifyChord(pitches)
+		if len(candidates) == 0 {
+			return fmt.Errorf("no chord matches %v", args)
+		}
+		for i, candidate := range candidates {
+			if i >= limit {
+				break
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "%-16s %-16s %.1f\n", candidate.Chord, inversionNames[candidate.Inversion], candidate.Score)
+		}
+		return nil
+	},
+}
+
+var transposeCmd = &cobra.Command{
+	Use:   "transpose [chords...]",
+	Short: "Transpose chord symbols",
+	Long: `Transpose chord symbols by a number of semitones, e.g. "transpose -s 2 Bbm7 Eb7 Abmaj7".
+By default the notes are spelled for the key of the transposed progression.`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		semitones, err := cmd.Flags().GetInt("semitones")
+		if err != nil {
+			panic(err)
+		}
+		spelling, err := cmd.Flags().GetString("spelling")
+		if err != nil {
+			panic(err)
+		}
+		chords, err := parseChords(args, nil)
+		if err != nil {
+			return err
+		}
+
+		progression := godio.Progression(chords)
+		switch spelling {
+		case "key":
+			progression = progression.Transpose(semitones)
+		case "sharps":
+			progression = progression.TransposeWith(semitones, godio.SharpSpelling)
+		case "flats":
+			progression = progression.TransposeWith(semitones, godio.FlatSpelling)
+		default:
+			return fmt.Errorf("unknown spelling %q, expected key, sharps or flats", spelling)
+		}
+		fmt.Fprintln(cmd.OutOrStdout(), progression)
+		return nil
+	},
+}
+
+var guitarCmd = &cobra.Command{
+	Use:        "guitar [chord]",
+	Short:      "Print guitar fingerings of a chord",
+	Long:       `Print chord diagrams of the easiest guitar fingerings of a chord, e.g. "guitar Cmaj7 --strings dropd".`,
+	Args:       cobra.ExactArgs(1),
+	ArgAliases: []string{"chord"},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		openStrings, err := cmd.Flags().GetString("strings")
+		if err != nil {
+			panic(err)
+		}
+		limit, err := cmd.Flags().GetInt("limit")
+		if err != nil {
+			panic(err)
+		}
+		span, err := cmd.Flags().GetInt("span")
+		if err != nil {
+			panic(err)
+		}
+		duration, err := cmd.Flags().GetFloat64("duration")
+		if err != nil {
+			panic(err)
+		}
+		waveform, err := cmd.Flags().GetString("waveform")
+		if err != nil {
+			panic(err)
+		}
+		output, err := cmd.Flags().GetString("output")
+		if err != nil {
+			panic(err)
+		}
+
+		chord, err := godio.ParseChordE(args[0])
+		if err != nil {
+			return err
+		}
+		guitarTuning, err := guitar.ParseTuning(openStrings)
+		if err != nil {
+			return err
+		}
+		fretboard := guitar.NewFretboard(guitarTuning)
+		fretboard.MaxSpan = span
+		fingerings := fretboard.Fingerings(chord)
+		if len(fingerings) == 0 {
+			return fmt.Errorf("no playable fingering of %v in %v", chord, guitarTuning)
+		}
+		for i, fingering := range fingerings {
+			if i >= limit {
+				break
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "%v %v\n%v\n", chord, fingering, fingering.Diagram())
+		}
+
+		if output == "" {
+			return nil
+		}
+		tuning, err := getTuning(cmd)
+		if err != nil {
+			return err
+		}
+		sb, err := newSoundBuffer(cmd)
+		if err != nil {
+			return err
+		}
+		if err := guitar.Render(sb, fingerings[0], guitarTuning, tuning, duration, godio.Waveform(waveform)); err != nil {
+			return err
+		}
+
+		wavFile, err := os.Create(output)
+		if err != nil {
+			panic(err)
+		}
+
+		if err := sb.Write(wavFile); err != nil {
+			panic(err)
+		}
+		return nil
+	},
+}
+
+var showCmd = &cobra.Command{
+	Use:        "show [chord]",
+	Short:      "Draw a voiced chord",
+	Long:       `Draw the voicing of a chord on a piano keyboard, or its easiest fingering on a guitar, as ASCII art or SVG.`,
+	Args:       cobra.ExactArgs(1),
+	ArgAliases: []string{"chord"},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		v2, err := cmd.Flags().GetBool("v2")
+		if err != nil {
+			panic(err)
+		}
+		format, err := cmd.Flags().GetString("format")
+		if err != nil {
+			panic(err)
+		}
+		instrument, err := cmd.Flags().GetString("instrument")
+		if err != nil {
+			panic(err)
+		}
+		openStrings, err := cmd.Flags().GetString("strings")
+		if err != nil {
+			panic(err)
+		}
+		output, err := cmd.Flags().GetString("output")
+		if err != nil {
+			panic(err)
+		}
+		if format != "ascii" && format != "svg" {
+			return fmt.Errorf("unknown format %q, expected ascii or svg", format)
+		}
+
+		chord, err := godio.ParseChordE(args[0])
+		if err != nil {
+			return err
+		}
+		chord.Style, err = getVoicingStyle(cmd)
+		if err != nil {
+			return err
+		}
+
+		var diagram string
+		switch instrument {
+		case "piano":
+			frequencies := chord.GetFrequencies()
+			if v2 {
+				frequencies = chord.GetFrequenciesV2()
+			}
+			notes := lo.Map(frequencies, func(frequency float64, _ int) int {
+				return godio.StandardTuning.Note(frequency)
+			})
+			diagram = godio.KeyboardDiagram(notes)
+			if format == "svg" {
+				diagram = godio.KeyboardSVG(notes)
+			}
+		case "guitar":
+			guitarTuning, err := guitar.ParseTuning(openStrings)
+			if err != nil {
+				return err
+			}
+			fingerings := guitar.NewFretboard(guitarTuning).Fingerings(chord)
+			if len(fingerings) == 0 {
+				return fmt.Errorf("no playable fingering of %v in %v", chord, guitarTuning)
+			}
+			diagram = fingerings[0].Diagram()
+			if format == "svg" {
+				diagram = fingerings[0].SVG()
+			}
+		default:
+			return fmt.Errorf("unknown instrument %q, expected piano or guitar", instrument)
+		}
+
+		if output == "" {
+			_, err := fmt.Fprint(cmd.OutOrStdout(), diagram)
+			return err
+		}
+		return os.WriteFile(output, []byte(diagram), 0o644)
+	},
+}
+
+var scaleCmd = &cobra.Command{
+	Use:   "scale [tonic] [mode]",
+	Short: "Generate a scale",
+	Long: `Generate a scale or mode going up from its tonic, e.g. "scale D dorian" or "scale Bb altered".
+Known scales: ` + strings.Join(godio.ScaleNames(), ", ") + `.`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		duration, err := cmd.Flags().GetFloat64("duration")
+		if err != nil {
+			panic(err)
+		}
+		waveform, err := cmd.Flags().GetString("waveform")
+		if err != nil {
+			panic(err)
+		}
+		output, err := cmd.Flags().GetString("output")
+		if err != nil {
+			panic(err)
+		}
+		octave, err := cmd.Flags().GetInt("octave")
+		if err != nil {
+			panic(err)
+		}
+		descend, err := cmd.Flags().GetBool("descend")
+		if err != nil {
+			panic(err)
+		}
+
+		scale, err := godio.ParseScale(strings.Join(args, " "))
+		if err != nil {
+			return err
+		}
+		tuning, err := getTuning(cmd)
+		if err != nil {
+			return err
+		}
+
+		pitches := scale.Pitches(octave)
+		fmt.Fprintf(cmd.OutOrStdout(), "%v: %v\n", scale, pitches)
+		if descend {
+			pitches = append(pitches, lo.Reverse(slices.Clone(pitches[:len(pitches)-1]))...)
+		}
+
+		sb, err := newSoundBuffer(cmd)
+		if err != nil {
+			return err
+		}
+		for _, pitch := range pitches {
+			sb.AppendNote(tuning.Frequency(pitch.MIDI()), duration, godio.Waveform(waveform))
+		}
+
+		wavFile, err := os.Create(output)
+		if err != nil {
+			panic(err)
+		}
+
+		if err := sb.Write(wavFile); err != nil {
+			panic(err)
+		}
+		return nil
+	},
+}
+
+var analyzeCmd = &cobra.Command{
+	Use:   "analyze [chords...]",
+	Short: "Analyze a chord progression",
+	Long: `Label the chords of a progression with Roman numerals and functions in its likeliest key, e.g. "analyze Dm7 G7 Cmaj7 A7".
+Secondary dominants, tritone substitutes, borrowed chords and ii-V units are flagged.`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		keyName, err := cmd.Flags().GetString("key")
+		if err != nil {
+			panic(err)
+		}
+		format, err := cmd.Flags().GetString("format")
+		if err != nil {
+			panic(err)
+		}
+		var key *godio.Key
+		if keyName != "" {
+			parsedKey, err := godio.ParseKey(keyName)
+			if err != nil {
+				return err
+			}
+			key = &parsedKey
+		}
+		chords, err := parseChords(args, key)
+		if err != nil {
+			return err
+		}
+
+		analysis := godio.AnalyzeProgression(chords)
+		if key != nil {
+			analysis = key.AnalyzeProgression(chords)
+		}
+		switch format {
+		case "json":
+			data, err := json.MarshalIndent(analysis, "", "  ")
+			if err != nil {
+				return err
+			}
+			fmt.Fprintln(cmd.OutOrStdout(), string(data))
+		case "table":
+			keys := lo.Map(analysis.Keys, func(score godio.KeyScore, _ int) string { return score.Key.String() })
+			fmt.Fprintf(cmd.OutOrStdout(), "Key: %v (likeliest: %s)\n", analysis.Key, strings.Join(keys, ", "))
+			for i, chord := range analysis.Chords {
+				row := fmt.Sprintf("%-10s %-12s %-12s %s", chord.Chord, chord.Numeral, chord.Function, strings.Join(analysisNotes(analysis, i), ", "))
+				fmt.Fprintln(cmd.OutOrStdout(), strings.TrimRight(row, " "))
+			}
+		default:
+			return fmt.Errorf("unknown format %q, expected table or json", format)
+		}
+		return nil
+	},
+}
+
+// analysisNotes describes how the chord at an index of an analysis departs from
+// its key and the ii-V units it belongs to
+func analysisNotes(analysis *godio.ProgressionAnalysis, index int) []string {
+	chord := analysis.Chords[index]
+	notes := []string{}
+	switch {
+	case chord.SecondaryDominant:
+		notes = append(notes, "secondary dominant")
+	case chord.TritoneSubstitute:
+		notes = append(notes, "tritone substitute")
+	case chord.Borrowed != "":
+		notes = append(notes, "borrowed from "+chord.Borrowed)
+	case !chord.Diatonic:
+		notes = append(notes, "chromatic")
+	}
+	for _, twoFive := range analysis.TwoFives {
+		switch index {
+		case twoFive.Index:
+			notes = append(notes, fmt.Sprintf("ii of a ii-V to %v", twoFive.Key))
+		case twoFive.Index + 1:
+			notes = append(notes, fmt.Sprintf("V of a ii-V to %v", twoFive.Key))
+		}
+	}
+	return notes
+}
+
+var reharmCmd = &cobra.Command{
+	Use:   "reharm [chords...]",
+	Short: "Reharmonize a chord progression",
+	Long: `Rewrite a chord progression with reharmonization transforms, e.g. "reharm --seed 7 Dm7 G7 Cmaj7".
+Known transforms:
+` + strings.Join(lo.Map(reharm.Transforms, func(transform reharm.Transform, _ int) string {
+		return fmt.Sprintf("  %-10s %s", transform.Name, transform.Description)
+	}), "\n"),
+	Args: cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		keyName, err := cmd.Flags().GetString("key")
+		if err != nil {
+			panic(err)
+		}
+		names, err := cmd.Flags().GetStringSlice("transforms")
+		if err != nil {
+			panic(err)
+		}
+		probability, err := cmd.Flags().GetFloat64("probability")
+		if err != nil {
+			panic(err)
+		}
+		seed, err := cmd.Flags().GetInt64("seed")
+		if err != nil {
+			panic(err)
+		}
+		duration, err := cmd.Flags().GetFloat64("duration")
+		if err != nil {
+			panic(err)
+		}
+		waveform, err := cmd.Flags().GetString("waveform")
+		if err != nil {
+			panic(err)
+		}
+		output, err := cmd.Flags().GetString("output")
+		if err != nil {
+			panic(err)
+		}
+
+		var key *godio.Key
+		if keyName != "" {
+			parsedKey, err := godio.ParseKey(keyName)
+			if err != nil {
+				return err
+			}
+			key = &parsedKey
+		}
+		chords, err := parseChords(args, key)
+		if err != nil {
+			return err
+		}
+		reharmonizer := reharm.NewReharmonizer(seed)
+		reharmonizer.Probability = probability
+		reharmonizer.Transforms = []reharm.Transform{}
+		for _, name := range names {
+			transform, err := reharm.TransformByName(name)
+			if err != nil {
+				return err
+			}
+			reharmonizer.Transforms = append(reharmonizer.Transforms, transform)
+		}
+
+		progression := reharmonizer.Reharmonize(chords)
+		fmt.Fprintln(cmd.OutOrStdout(), progression)
+		if output == "" {
+			return nil
+		}
+
+		tuning, err := getTuning(cmd)
+		if err != nil {
+			return err
+		}
+		sb, err := newSoundBuffer(cmd)
+		if err != nil {
+			return err
+		}
+		for _, voicing := range godio.NewVoicer().Voice(progression) {
+			sb.AppendChord(voicing.Frequencies(tuning), duration, godio.Waveform(waveform))
+		}
+		sb.ApplyADSR(godio.ADSREnvelope{
+			Attack:  1,
+			Decay:   int(duration * 1000),
+			Sustain: 0,
+			Release: 0,
+		})
+
+		wavFile, err := os.Create(output)
+		if err != nil {
+			panic(err)
+		}
+
+		if err := sb.Write(wavFile); err != nil {
+			panic(err)
+		}
+		return nil
 	},
 }
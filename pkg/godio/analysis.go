@@ -0,0 +1,312 @@
+package godio
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/samber/lo"
+)
+
+// HarmonicFunction is the role of a chord in its key
+type HarmonicFunction string
+
+const (
+	FunctionTonic       HarmonicFunction = "tonic"
+	FunctionPredominant HarmonicFunction = "predominant"
+	FunctionDominant    HarmonicFunction = "dominant"
+)
+
+// ChordAnalysis labels a chord of a progression in the key of the progression
+type ChordAnalysis struct {
+	Chord             string           `json:"chord"`
+	Numeral           string           `json:"numeral"` // Roman numeral such as "ii7", "bVII" or "V7/V"
+	Function          HarmonicFunction `json:"function"`
+	Diatonic          bool             `json:"diatonic"`
+	SecondaryDominant bool             `json:"secondaryDominant"`
+	TritoneSubstitute bool             `json:"tritoneSubstitute"`
+	Borrowed          string           `json:"borrowed,omitempty"` // Parallel mode of a modal interchange chord, such as "aeolian"
+}
+
+// TwoFive is a ii–V unit, a minor or half-diminished chord followed by a dominant a fourth above
+type TwoFive struct {
+	Index    int  `json:"index"`    // Index of the ii chord in the progression
+	Key      Key  `json:"key"`      // Key of the I it leads to, minor after a half-diminished ii
+	Resolved bool `json:"resolved"` // Whether the V is followed by that I
+}
+
+// ProgressionAnalysis is the harmonic analysis of a progression
+type ProgressionAnalysis struct {
+	Key      Key             `json:"key"`  // Key the chords are labelled in
+	Keys     []KeyScore      `json:"keys"` // Likeliest keys of the progression, best first
+	Chords   []ChordAnalysis `json:"chords"`
+	TwoFives []TwoFive       `json:"twoFives"`
+}
+
+// analysisKeys is the number of likeliest keys listed by an analysis
+const analysisKeys = 3
+
+// harmonicFunctions are the functions of the chords whose root lies each number
+// of semitones above the tonic, for chords that are not secondary dominants or
+// tritone substitutes. Borrowed chords such as bVI and bVII are predominant.
+var harmonicFunctions = map[KeyMode][]HarmonicFunction{
+	ModeMajor: {
+		FunctionTonic, FunctionPredominant, FunctionPredominant, FunctionTonic, FunctionTonic, FunctionPredominant,
+		FunctionPredominant, FunctionDominant, FunctionPredominant, FunctionTonic, FunctionPredominant, FunctionDominant,
+	},
+	ModeMinor: {
+		FunctionTonic, FunctionPredominant, FunctionPredominant, FunctionTonic, FunctionTonic, FunctionPredominant,
+		FunctionPredominant, FunctionDominant, FunctionPredominant, FunctionPredominant, FunctionDominant, FunctionDominant,
+	},
+}
+
+// chromaticDegrees are the degree and accidental of the Roman numerals of roots
+// outside the scale of each mode, by semitones above the tonic
+var chromaticDegrees = map[KeyMode]map[int]struct{ degree, accidental int }{
+	ModeMajor: {1: {2, -1}, 3: {3, -1}, 6: {4, 1}, 8: {6, -1}, 10: {7, -1}},
+	ModeMinor: {1: {2, -1}, 4: {3, 1}, 6: {4, 1}, 9: {6, 1}, 11: {7, 1}},
+}
+
+// parallelModes are the modes on the same tonic that modal interchange chords
+// are borrowed from, the most common first
+var parallelModes = map[KeyMode][]string{
+	ModeMajor: {"aeolian", "dorian", "mixolydian", "phrygian", "lydian"},
+	ModeMinor: {"dorian", "ionian", "phrygian", "mixolydian", "lydian"},
+}
+
+// AnalyzeProgression analyzes the chords of a progression in its estimated key
+func AnalyzeProgression(p Progression) *ProgressionAnalysis {
+	return p.EstimateKey().AnalyzeProgression(p)
+}
+
+// AnalyzeProgression labels each chord of a progression with its Roman numeral
+// and function in the key. Chromatic chords are flagged as secondary dominants,
+// tritone substitutes or chords borrowed from a parallel mode, and consecutive
+// ii and V chords are listed as ii–V units.
+//
+// A dominant seventh chord a fifth above a degree other than the tonic is a
+// secondary dominant, as is a major triad resolving to such a degree. A
+// dominant seventh chord resolving down a half step is a tritone substitute.
+func (k Key) AnalyzeProgression(p Progression) *ProgressionAnalysis {
+	analysis := &ProgressionAnalysis{
+		Key:      k,
+		Keys:     p.KeyScores()[:analysisKeys],
+		Chords:   []ChordAnalysis{},
+		TwoFives: []TwoFive{},
+	}
+	for i, chord := range p {
+		var next *Chord
+		if i+1 < len(p) {
+			next = p[i+1]
+		}
+		analysis.Chords = append(analysis.Chords, k.analyzeChord(chord, next))
+
+		if next != nil && isTwoFive(chord, next) {
+			twoFive := TwoFive{Index: i, Key: Key{Tonic: next.Root.Add(PerfectFourth), Mode: ModeMajor}}
+			if chord.triadQuality() == "dim" {
+				twoFive.Key.Mode = ModeMinor
+			}
+			twoFive.Resolved = i+2 < len(p) && p[i+2].Root.PitchClass() == twoFive.Key.Tonic.PitchClass()
+			analysis.TwoFives = append(analysis.TwoFives, twoFive)
+		}
+	}
+	return analysis
+}
+
+// analyzeChord labels a chord of a progression followed by next, which is nil for the last chord
+func (k Key) analyzeChord(c *Chord, next *Chord) ChordAnalysis {
+	interval := k.interval(c.Root)
+	analysis := ChordAnalysis{
+		Chord:    c.String(),
+		Numeral:  k.romanNumeral(c),
+		Function: harmonicFunctions[k.Mode][interval],
+		Diatonic: lo.Every(k.scaleClasses(), c.harmonyClasses()),
+	}
+	if analysis.Diatonic {
+		return analysis
+	}
+
+	dominant := (c.triadQuality() == "maj" || c.triadQuality() == "aug") && (c.seventh() == 0 || c.seventh() == 10)
+	resolvesTo := func(semitones int) bool {
+		return next != nil && k.interval(next.Root) == mod12(interval-semitones)
+	}
+	if target, ok := k.targetNumeral(mod12(interval - 1)); ok && dominant && c.seventh() == 10 && resolvesTo(1) {
+		analysis.Numeral = "sub" + c.romanFigure(5) + target
+		analysis.Function = FunctionDominant
+		analysis.TritoneSubstitute = true
+		return analysis
+	}
+	if target, ok := k.targetNumeral(mod12(interval - 7)); ok && target != "" && dominant && (c.seventh() == 10 || resolvesTo(7)) {
+		analysis.Numeral = c.romanFigure(5) + target
+		analysis.Function = FunctionDominant
+		analysis.SecondaryDominant = true
+		return analysis
+	}
+	for _, mode := range parallelModes[k.Mode] {
+		scale, _ := NewScale(k.Tonic, mode)
+		if lo.Every(scale.PitchClasses(), c.harmonyClasses()) {
+			analysis.Borrowed = mode
+			break
+		}
+	}
+	return analysis
+}
+
+// interval returns the number of semitones from the tonic of the key up to a note, from 0 to 11
+func (k Key) interval(note SpelledNote) int {
+	return mod12(int(note.PitchClass()) - int(k.Tonic.PitchClass()))
+}
+
+// scaleClasses returns the pitch classes of the scale of the key. Minor keys
+// also have the raised leading tone of their major dominant.
+func (k Key) scaleClasses() []int {
+	classes := lo.Map(scaleDegrees[k.Mode], func(semitones int, _ int) int {
+		return int(k.Tonic.PitchClass().Add(Semitones(semitones)))
+	})
+	if k.Mode == ModeMinor {
+		classes = append(classes, int(k.Tonic.PitchClass().Add(MajorSeventh)))
+	}
+	return classes
+}
+
+// romanNumeral writes the chord as a Roman numeral of the key, with a flat or a
+// sharp for roots outside its scale as in "bVII" or "#iv°"
+func (k Key) romanNumeral(c *Chord) string {
+	interval := k.interval(c.Root)
+	degree, accidental := slices.Index(scaleDegrees[k.Mode], interval)+1, 0
+	if degree == 0 {
+		chromatic := chromaticDegrees[k.Mode][interval]
+		degree, accidental = chromatic.degree, chromatic.accidental
+	}
+	prefix := strings.Repeat("#", max(0, accidental)) + strings.Repeat("b", max(0, -accidental))
+	return prefix + c.romanFigure(degree)
+}
+
+// targetNumeral writes the "/ii" of a secondary chord leading to the degree a
+// number of semitones above the tonic, or nothing when it leads to the tonic.
+// It reports false for notes outside the scale and diminished degrees, which
+// are not targets.
+func (k Key) targetNumeral(semitones int) (string, bool) {
+	degree := slices.Index(scaleDegrees[k.Mode], semitones) + 1
+	if degree == 0 {
+		return "", false
+	}
+	triads := diatonicTriads[k.Mode][degree-1]
+	switch triad := triads[len(triads)-1]; {
+	case triad == "dim":
+		return "", false
+	case degree == 1:
+		return "", true
+	case triad == "m":
+		return "/" + strings.ToLower(degreeNumeral(degree)), true
+	}
+	return "/" + degreeNumeral(degree), true
+}
+
+// degreeNumeral returns the uppercase Roman numeral of a degree from 1 to 7
+func degreeNumeral(degree int) string {
+	for _, roman := range romanNumerals {
+		if roman.degree == degree {
+			return roman.numeral
+		}
+	}
+	return ""
+}
+
+// romanFigure writes the chord as the Roman numeral of a degree, uppercase for
+// major, augmented and suspended chords and lowercase for minor and diminished
+// ones, followed by its quality, seventh and inversion figure as accepted by
+// ParseRomanNumeral. Tensions are left out, so that G7b9 is a V7.
+func (c Chord) romanFigure(degree int) string {
+	numeral := degreeNumeral(degree)
+	triad, seventh := c.triadQuality(), c.seventh()
+	if triad == "m" || triad == "dim" {
+		numeral = strings.ToLower(numeral)
+	}
+
+	quality := map[string]string{"dim": "°", "aug": "+"}[triad]
+	switch {
+	case triad == "dim" && seventh == 10:
+		quality = "ø7"
+	case seventh == 11:
+		quality += "maj7"
+	case seventh != 0:
+		quality += "7"
+	}
+
+	bass := -1
+	if !c.BassNote.IsZero() {
+		bass = mod12(int(c.BassNote.PitchClass()) - int(c.Root.PitchClass()))
+	}
+	switch {
+	case seventh != 0 && bass == seventh:
+		quality = strings.TrimSuffix(quality, "7") + "42"
+	case seventh != 0 && (bass == 3 || bass == 4):
+		quality = strings.TrimSuffix(quality, "7") + "65"
+	case seventh != 0 && bass >= 6 && bass <= 8:
+		quality = strings.TrimSuffix(quality, "7") + "43"
+	case bass == 3 || bass == 4:
+		quality += "6"
+	case bass >= 6 && bass <= 8:
+		quality += "64"
+	}
+
+	if triad == "" && c.hasTone(5) {
+		quality += "sus4"
+	} else if triad == "" && c.hasTone(2) {
+		quality += "sus2"
+	}
+	return numeral + quality
+}
+
+// seventh returns the number of semitones from the root up to the seventh of the
+// chord, or 0 when it has none. The diminished seventh is 9 semitones above the root.
+func (c Chord) seventh() int {
+	switch {
+	case c.hasTone(10):
+		return 10
+	case c.hasTone(11):
+		return 11
+	case c.triadQuality() == "dim" && c.hasTone(9):
+		return 9
+	}
+	return 0
+}
+
+// harmonyClasses returns the pitch classes of the root, third, fifth, seventh and
+// bass note of the chord, leaving out tensions. The fifth is included even when
+// it is omitted.
+func (c Chord) harmonyClasses() []int {
+	semitones := []int{0}
+	switch c.triadQuality() {
+	case "dim":
+		semitones = append(semitones, 3, 6)
+	case "aug":
+		semitones = append(semitones, 4, 8)
+	case "m":
+		semitones = append(semitones, 3, 7)
+	case "maj":
+		semitones = append(semitones, 4, 7)
+	default:
+		semitones = append(semitones, 7)
+		semitones = append(semitones, lo.Filter([]int{2, 5}, func(tone int, _ int) bool { return c.hasTone(tone) })...)
+	}
+	if seventh := c.seventh(); seventh != 0 {
+		semitones = append(semitones, seventh)
+	}
+
+	classes := lo.Map(semitones, func(tone int, _ int) int {
+		return int(c.Root.PitchClass().Add(Semitones(tone)))
+	})
+	if !c.BassNote.IsZero() {
+		classes = append(classes, int(c.BassNote.PitchClass()))
+	}
+	return classes
+}
+
+// isTwoFive reports whether a minor or diminished chord followed by a dominant
+// chord a fourth above form a ii–V unit
+func isTwoFive(ii *Chord, v *Chord) bool {
+	minor := (ii.triadQuality() == "m" || ii.triadQuality() == "dim") && (ii.seventh() == 0 || ii.seventh() == 10)
+	dominant := v.triadQuality() == "maj" && (v.seventh() == 0 || v.seventh() == 10)
+	return minor && dominant && mod12(int(v.Root.PitchClass())-int(ii.Root.PitchClass())) == 5
+}
@@ -0,0 +1,139 @@
+package godio
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/samber/lo"
+)
+
+func parseProgression(symbols string) Progression {
+	return lo.Map(strings.Fields(symbols), func(symbol string, _ int) *Chord {
+		return MustParseChord(symbol)
+	})
+}
+
+func TestAnalyzeProgression(t *testing.T) {
+	parameters := []struct {
+		progression string
+		key         string
+		numerals    string
+		functions   string
+	}{
+		{"C Am F G C", "C major", "I vi IV V I", "T T PD D T"},
+		{"Dm7 G7 Cmaj7 A7 Dm7 Db7 Cmaj7", "C major", "ii7 V7 Imaj7 V7/ii ii7 subV7 Imaj7", "PD D T D PD D T"},
+		{"C E7 Am D G7 C", "C major", "I V7/vi vi V/V V7 I", "T D T D D T"},
+		{"Cmaj7 Fm Ab Bb7 Cmaj7", "C major", "Imaj7 iv bVI bVII7 Imaj7", "T PD PD PD T"},
+		{"Cm Fm G7 Ab Dø7 G7 Cm", "C minor", "i iv V7 VI iiø7 V7 i", "T PD D PD PD D T"},
+		{"Em7b5 A7 Dm", "D minor", "iiø7 V7 i", "PD D T"},
+		{"Cm7b5 F7 Bbm", "Bb minor", "iiø7 V7 i", "PD D T"},
+		{"F#m7b5 B7 Em", "E minor", "iiø7 V7 i", "PD D T"},
+		{"C/E F G7/F C", "C major", "I6 IV V42 I", "T PD D T"},
+		{"Eb Ab Bbsus4 Bb Eb", "Eb major", "I IV Vsus4 V I", "T PD D D T"},
+		{"Cmaj7 Dm7 G7#9 Cmaj7 A7#9 Dm7", "C major", "Imaj7 ii7 V7 Imaj7 V7/ii ii7", "T PD D T D PD"},
+	}
+	functions := map[string]HarmonicFunction{"T": FunctionTonic, "PD": FunctionPredominant, "D": FunctionDominant}
+
+	for _, p := range parameters {
+		t.Run(fmt.Sprintf("Testing %v", p.progression), func(t *testing.T) {
+			analysis := AnalyzeProgression(parseProgression(p.progression))
+			if analysis.Key.String() != p.key {
+				t.Errorf("Expected the key %v, but got %v", p.key, analysis.Key)
+			}
+			numerals := lo.Map(analysis.Chords, func(chord ChordAnalysis, _ int) string { return chord.Numeral })
+			if strings.Join(numerals, " ") != p.numerals {
+				t.Errorf("Expected %v, but got %v", p.numerals, strings.Join(numerals, " "))
+			}
+			for i, function := range strings.Fields(p.functions) {
+				if analysis.Chords[i].Function != functions[function] {
+					t.Errorf("Expected %v to be %v, but got %v", analysis.Chords[i].Chord, functions[function], analysis.Chords[i].Function)
+				}
+			}
+		})
+	}
+}
+
+func TestAnalyzeChromaticChords(t *testing.T) {
+	analysis := AnalyzeProgression(parseProgression("Cmaj7 A7 Dm7 Db7 Cmaj7 Fm6 Bb7 Cmaj7"))
+	parameters := []struct {
+		index             int
+		secondaryDominant bool
+		tritoneSubstitute bool
+		borrowed          string
+	}{
+		{0, false, false, ""},
+		{1, true, false, ""},
+		{3, false, true, ""},
+		{5, false, false, "aeolian"},
+		{6, false, false, "aeolian"},
+	}
+
+	for _, p := range parameters {
+		chord := analysis.Chords[p.index]
+		t.Run(fmt.Sprintf("Testing %v", chord.Chord), func(t *testing.T) {
+			if chord.SecondaryDominant != p.secondaryDominant {
+				t.Errorf("Expected a secondary dominant %v, but got %v", p.secondaryDominant, chord.SecondaryDominant)
+			}
+			if chord.TritoneSubstitute != p.tritoneSubstitute {
+				t.Errorf("Expected a tritone substitute %v, but got %v", p.tritoneSubstitute, chord.TritoneSubstitute)
+			}
+			if chord.Borrowed != p.borrowed {
+				t.Errorf("Expected borrowed from %q, but got %q", p.borrowed, chord.Borrowed)
+			}
+		})
+	}
+
+	for _, progression := range []string{"Em7b5 A7 Dm", "Cm7b5 F7 Bbm", "F#m7b5 B7 Em"} {
+		if borrowed := AnalyzeProgression(parseProgression(progression)).Chords[0].Borrowed; borrowed != "" {
+			t.Errorf("Expected the ii of %v not to be borrowed, but got %q", progression, borrowed)
+		}
+	}
+}
+
+func TestAnalyzeTwoFives(t *testing.T) {
+	parameters := []struct {
+		progression string
+		expected    []TwoFive
+	}{
+		{"Dm7 G7 Cmaj7", []TwoFive{{Index: 0, Key: Key{Tonic: ParseNote("C"), Mode: ModeMajor}, Resolved: true}}},
+		{"Em7 A7 Dm7 G7 Cmaj7", []TwoFive{
+			{Index: 0, Key: Key{Tonic: ParseNote("D"), Mode: ModeMajor}, Resolved: true},
+			{Index: 2, Key: Key{Tonic: ParseNote("C"), Mode: ModeMajor}, Resolved: true},
+		}},
+		{"Bø7 E7 C", []TwoFive{{Index: 0, Key: Key{Tonic: ParseNote("A"), Mode: ModeMinor}, Resolved: false}}},
+		{"Bm7b5 E7 Am", []TwoFive{{Index: 0, Key: Key{Tonic: ParseNote("A"), Mode: ModeMinor}, Resolved: true}}},
+		{"Em7b5 A7 Dm", []TwoFive{{Index: 0, Key: Key{Tonic: ParseNote("D"), Mode: ModeMinor}, Resolved: true}}},
+		{"Dm7 G7#9 Cmaj7", []TwoFive{{Index: 0, Key: Key{Tonic: ParseNote("C"), Mode: ModeMajor}, Resolved: true}}},
+		{"Cmaj7 Fmaj7", []TwoFive{}},
+	}
+
+	for _, p := range parameters {
+		t.Run(fmt.Sprintf("Testing %v", p.progression), func(t *testing.T) {
+			twoFives := AnalyzeProgression(parseProgression(p.progression)).TwoFives
+			if fmt.Sprint(twoFives) != fmt.Sprint(p.expected) {
+				t.Errorf("Expected %v, but got %v", p.expected, twoFives)
+			}
+		})
+	}
+}
+
+func TestAnalysisJSON(t *testing.T) {
+	key, _ := ParseKey("F")
+	analysis := key.AnalyzeProgression(parseProgression("Gm7 C7 F"))
+	data, err := json.Marshal(analysis)
+	if err != nil {
+		t.Fatalf("Expected JSON, but got %v", err)
+	}
+	for _, expected := range []string{`"key":"F major"`, `"numeral":"ii7"`, `"function":"dominant"`, `"twoFives":[{"index":0,"key":"F major","resolved":true}]`} {
+		if !strings.Contains(string(data), expected) {
+			t.Errorf("Expected %v in %s", expected, data)
+		}
+	}
+
+	var decoded ProgressionAnalysis
+	if err := json.Unmarshal(data, &decoded); err != nil || decoded.Key != key {
+		t.Errorf("Expected to decode the key %v, but got %v (%v)", key, decoded.Key, err)
+	}
+}
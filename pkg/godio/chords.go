@@ -1,63 +1,119 @@
 package godio
 
 import (
-	"math"
 	"slices"
 	"strings"
-	"unicode"
 
 	"github.com/samber/lo"
 )
 
 type Chord struct {
-	Root         string
+	Root         SpelledNote
 	Quality      string
-	BassNote     string
+	BassNote     SpelledNote
 	Extensions   []string
+	Omissions    []string
+	Upper        *Chord // Upper structure of a polychord
 	VoicingRules []VoicingRule
+	Style        VoicingStyle       // Voicing style of GetFrequencies, which keeps the tones between G3 and F#4 when empty
+	Tuning       Tuning             // Tuning of the frequencies, StandardTuning when nil
+	Constraints  VoicingConstraints // Limits applied to the notes of every voicing of the chord
 	Tones        []int
 }
 
-var chordFormulas = map[string][]int{
-	"":        {4, 7}, // It's reminiscent of figured bass with chromatic intervals instead of diatonic ones.
-	"maj":     {4, 7},
-	"aug":     {4, 8},
-	"dim":     {3, 6},
-	"dim7":    {3, 6, 9},
-	"m":       {3, 7},
-	"m7":      {3, 7, 10},
-	"7":       {4, 7, 10},
-	"maj7":    {4, 7, 11},
-	"9":       {4, 7, 10, 2},
-	"m9":      {3, 7, 10, 2},
-	"maj9":    {4, 7, 11, 2},
-	"13":      {4, 7, 10, 2, 9},
-	"m11":     {3, 7, 10, 14, -7}, // m11 chords are strange, and should be spaced properly. Maybe they shouldn't even have a 9.
-	"6":       {4, 7, 9},
-	"m6":      {3, 7, 9},
-	"69":      {4, 7, 9, 2},
-	"m69":     {3, 7, 9, 2},
-	"mmaj7":   {3, 7, 11},
-	"minmaj7": {3, 7, 11},
+// chordFormulas are the intervals above the root of the tones of each chord
+// quality besides the root. Ninths and thirteenths are written as seconds and
+// sixths, within the octave of the root, except in m11 chords. They are the
+// built-in qualities of NewChordRegistry.
+var chordFormulas = map[string][]Interval{
+	"":          {MajorThird, PerfectFifth}, // It's reminiscent of figured bass with chromatic intervals instead of diatonic ones.
+	"maj":       {MajorThird, PerfectFifth},
+	"aug":       {MajorThird, AugmentedFifth},
+	"dim":       {MinorThird, DiminishedFifth},
+	"dim7":      {MinorThird, DiminishedFifth, DiminishedSeventh},
+	"m":         {MinorThird, PerfectFifth},
+	"m7":        {MinorThird, PerfectFifth, MinorSeventh},
+	"7":         {MajorThird, PerfectFifth, MinorSeventh},
+	"maj7":      {MajorThird, PerfectFifth, MajorSeventh},
+	"9":         {MajorThird, PerfectFifth, MinorSeventh, MajorSecond},
+	"m9":        {MinorThird, PerfectFifth, MinorSeventh, MajorSecond},
+	"maj9":      {MajorThird, PerfectFifth, MajorSeventh, MajorSecond},
+	"13":        {MajorThird, PerfectFifth, MinorSeventh, MajorSecond, MajorSixth},
+	"m11":       {MinorThird, PerfectFifth, MinorSeventh, MajorNinth, PerfectFourth.Sub(PerfectOctave)}, // m11 chords are strange, and should be spaced properly. Maybe they shouldn't even have a 9.
+	"6":         {MajorThird, PerfectFifth, MajorSixth},
+	"m6":        {MinorThird, PerfectFifth, MajorSixth},
+	"69":        {MajorThird, PerfectFifth, MajorSixth, MajorSecond},
+	"m69":       {MinorThird, PerfectFifth, MajorSixth, MajorSecond},
+	"mmaj7":     {MinorThird, PerfectFifth, MajorSeventh},
+	"minmaj7":   {MinorThird, PerfectFifth, MajorSeventh},
+	"ø7":        {MinorThird, DiminishedFifth, MinorSeventh},
+	"aug7":      {MajorThird, AugmentedFifth, MinorSeventh},
+	"augmaj7":   {MajorThird, AugmentedFifth, MajorSeventh},
+	"dim(maj7)": {MinorThird, DiminishedFifth, MajorSeventh},
+	"7sus4b9":   {PerfectFourth, PerfectFifth, MinorSeventh, MinorSecond},
+	"5":         {PerfectFifth}, // Power chords have no third
 }
 
-var extensionFormulas = map[string]int{
-	"#1":   1,
-	"#15":  1,
-	"b9":   1,
-	"9":    2,
-	"#9":   3,
-	"11":   5,
-	"ll":   5,
-	"#11":  6,
-	"b5":   6,
-	"#5":   8,
-	"b13":  8,
-	"13":   9,
-	"sus":  5 - 12, // By having "sus" as an extension with a negative value, chords like C9sus will be properly parsed
-	"sus2": 2 - 12, // but still put the fourth of the chord lower as is typical for this kind of chord
-	"sus4": 5 - 12,
-	"maj7": 11,
+// extensionFormulas are the intervals above the root of the tone added by each
+// extension, written within the octave of the root like chordFormulas
+var extensionFormulas = map[string]Interval{
+	"#1":   AugmentedUnison,
+	"#15":  AugmentedUnison,
+	"2":    MajorSecond, // Added seconds and fourths, as in Cadd2add4, keep the third unlike suspensions
+	"4":    PerfectFourth,
+	"b9":   MinorSecond,
+	"9":    MajorSecond,
+	"#9":   AugmentedSecond,
+	"11":   PerfectFourth,
+	"ll":   PerfectFourth,
+	"#11":  AugmentedFourth,
+	"b5":   DiminishedFifth,
+	"#5":   AugmentedFifth,
+	"b13":  MinorSixth,
+	"13":   MajorSixth,
+	"sus":  PerfectFourth.Sub(PerfectOctave), // By having "sus" as an extension an octave down, chords like C9sus will be properly parsed
+	"sus2": MajorSecond.Sub(PerfectOctave),   // but still put the fourth of the chord lower as is typical for this kind of chord
+	"sus4": PerfectFourth.Sub(PerfectOctave),
+	"7":    MinorSeventh,
+	"maj7": MajorSeventh,
+}
+
+// tensionNames are the extensions naming the tones that are not part of a chord
+// formula by their interval above the root, as found by IdentifyChord and added
+// under a melody note by WithTopNote
+var tensionNames = map[int]string{
+	1:  "b9",
+	2:  "9",
+	3:  "#9",
+	5:  "11",
+	6:  "#11",
+	8:  "b13",
+	9:  "13",
+	11: "maj7",
+}
+
+// compoundExtensions are extensions standing for several extensionFormulas at once
+var compoundExtensions = map[string][]string{
+	"alt": {"b9", "#9", "#11", "b13"}, // Altered dominant tensions, as found in the altered scale
+}
+
+// isExtension reports whether extension is a known simple or compound extension
+func isExtension(extension string) bool {
+	if _, ok := compoundExtensions[extension]; ok {
+		return true
+	}
+	_, ok := extensionFormulas[extension]
+	return ok
+}
+
+// extensionTones returns the tones added by an extension
+func extensionTones(extension string) []int {
+	if extensions, ok := compoundExtensions[extension]; ok {
+		return lo.Map(extensions, func(e string, _ int) int {
+			return extensionFormulas[e].Semitones
+		})
+	}
+	return []int{extensionFormulas[extension].Semitones}
 }
 
 func (c *Chord) addTone(tone int) {
@@ -82,63 +138,146 @@ func (c *Chord) removeTone(tone int) {
 	})
 }
 
-func (c Chord) hasInterval(interval int) bool {
-	formula := chordFormulas[c.Quality]
+// hasInterval reports whether the formula of the chord quality has a tone the
+// same number of semitones above the root as the interval
+func (c Chord) hasInterval(interval Interval) bool {
+	formula := DefaultChordRegistry.formula(c.Quality)
 	for _, i := range formula {
-		if i == interval {
+		if i.Semitones == interval.Semitones {
 			return true
 		}
 	}
 	return false
 }
 
+// PitchClasses returns the sorted pitch classes, from 0 for C to 11 for B, of the chord tones and bass note
+func (c Chord) PitchClasses() []int {
+	classes := []int{}
+	if !c.BassNote.IsZero() {
+		classes = append(classes, int(c.BassNote.PitchClass()))
+	}
+	for _, tone := range c.Tones {
+		classes = append(classes, int(c.Root.PitchClass().Add(Semitones(tone))))
+	}
+	classes = lo.Uniq(classes)
+	slices.Sort(classes)
+	return classes
+}
+
+// bass returns the bass note of the chord, which is the root unless BassNote is set
+func (c Chord) bass() SpelledNote {
+	if c.BassNote.IsZero() {
+		return c.Root
+	}
+	return c.BassNote
+}
+
+// GetFrequencies returns the frequencies of the bass and of the chord tones. The
+// upper structure of a polychord is voiced in close position above the lower chord.
 func (c Chord) GetFrequencies() []float64 {
-	var frequencies []float64
-	var bassNoteName string
-	if c.BassNote != "" {
-		bassNoteName = c.BassNote + "2"
-	} else {
-		bassNoteName = c.Root + "2"
+	tuning := c.tuning()
+	if c.Style != "" {
+		return c.Voice(c.Style).Frequencies(tuning)
+	}
+	voicing := c.Lower().closeVoicing()
+	if c.Upper != nil {
+		voicing = append(voicing, c.Upper.voiceAbove(slices.Max(voicing))...)
 	}
-	rootNoteName := c.Root + "3"
+	return c.constrain(voicing).Frequencies(tuning)
+}
+
+// closeVoicing returns the bass in octave 2 and the chord tones between G3 and
+// F#4, after applying the voicing rules
+func (c Chord) closeVoicing() Voicing {
+	bass := c.bass().PitchClass().Pitch(2)
+	root := c.Root.PitchClass().Pitch(3).MIDI()
+	// Chord tones are kept between G3 and F#4
+	lowest, highest := ParsePitch("G3").MIDI(), ParsePitch("F#4").MIDI()
 
 	c.applyVoicingRules()
 
-	frequencies = append(frequencies, NoteFrequencies[bassNoteName])
+	voicing := Voicing{bass.MIDI()}
 
 	for _, interval := range c.Tones {
-		intervalFrequency := NoteFrequencies.GetNoteFromInterval(rootNoteName, interval)
-		if intervalFrequency > NoteFrequencies["F#4"] {
-			intervalFrequency = NoteFrequencies.GetNoteFromInterval(rootNoteName, interval-12)
+		note := root + interval
+		if note > highest {
+			note -= 12
 		}
-		if intervalFrequency < NoteFrequencies["G3"] {
-			intervalFrequency = NoteFrequencies.GetNoteFromInterval(rootNoteName, interval+12)
+		if note < lowest {
+			note += 12
 		}
-		frequencies = append(frequencies, intervalFrequency)
+		voicing = append(voicing, note)
 	}
+	return voicing
+}
+
+// voiceAbove returns the tones of the upper structure of a polychord in close
+// position, from the lowest root above a MIDI note number
+func (c Chord) voiceAbove(note int) Voicing {
+	c.Tones = slices.Clone(c.Tones)
+	c.applyVoicingRules()
+	root := note + 1 + mod12(int(c.Root.PitchClass())-note-1)
+	voicing := Voicing{}
+	for _, tone := range c.Tones {
+		voicing = append(voicing, root+mod12(tone))
+	}
+	slices.Sort(voicing)
+	return slices.Compact(voicing)
+}
 
-	return frequencies
+// Lower returns the lower chord of a polychord, without the upper structure
+// and its tones, or the chord itself when it has no upper structure
+func (c Chord) Lower() Chord {
+	if c.Upper == nil {
+		return c
+	}
+	tones := slices.Clone(c.Tones)
+	for _, tone := range c.upperTones() {
+		// The upper tones were appended last, and may since have been moved by WithTopNote
+		if i := lastIndex(tones, tone); i >= 0 {
+			tones = slices.Delete(tones, i, i+1)
+		}
+	}
+	c.Tones = tones
+	c.Upper = nil
+	return c
 }
 
-func noteToNumber(note string) int {
-	notes := map[byte]int{
-		'C': 12,
-		'D': 14,
-		'E': 16,
-		'F': 17,
-		'G': 19,
-		'A': 21,
-		'B': 23,
-	}
-	accidentals := map[rune]int{
-		'b': -1,
-		'#': 1,
-	}
-	value := notes[note[0]]
-	for _, item := range note[1:] {
-		value += accidentals[item]
-	}
-	return value
+// upperTones returns the tones of the upper structure of a polychord as tones of
+// the chord, an octave above its root
+func (c Chord) upperTones() []int {
+	if c.Upper == nil {
+		return nil
+	}
+	offset := int(c.Upper.Root.PitchClass()) - int(c.Root.PitchClass())
+	return lo.Map(c.Upper.Tones, func(tone int, _ int) int { return 12 + mod12(offset+tone) })
+}
+
+// lastIndex returns the index of the last occurrence of a tone, or -1 if there is none
+func lastIndex(tones []int, tone int) int {
+	for i := len(tones) - 1; i >= 0; i-- {
+		if tones[i] == tone {
+			return i
+		}
+	}
+	return -1
+}
+
+// constrain applies the constraints of the chord to a voicing, which is kept
+// as is when there are none.
+func (c Chord) constrain(voicing Voicing) Voicing {
+	if c.Constraints.IsZero() {
+		return voicing
+	}
+	return c.Constraints.Apply(voicing)
+}
+
+// tuning returns the tuning of the chord, which is StandardTuning unless Tuning is set
+func (c Chord) tuning() Tuning {
+	if c.Tuning == nil {
+		return StandardTuning
+	}
+	return c.Tuning
 }
 
 func (c Chord) GetFrequenciesV2() []float64 {
@@ -150,10 +289,11 @@ func (c Chord) GetFrequenciesV2() []float64 {
 		extensionOctave = 60
 	}
 
-	root := noteToNumber(c.Root)
-	bassNote := noteToNumber(c.BassNote)
+	// MIDI note numbers in octave 0, from 12 for C0
+	root := c.Root.Pitch(0).MIDI()
+	bassNote := c.bass().Pitch(0).MIDI()
 
-	chordTones := chordFormulas[c.Quality]
+	chordTones := semitoneOffsets(DefaultChordRegistry.formula(c.Quality))
 	if slices.Contains(c.Extensions, "sus") || slices.Contains(c.Extensions, "sus2") || slices.Contains(c.Extensions, "sus4") {
 		for index, tone := range chordTones {
 			if tone == 3 || tone == 4 {
@@ -170,9 +310,11 @@ func (c Chord) GetFrequenciesV2() []float64 {
 		}
 	}
 
+	omitted := lo.FlatMap(c.Omissions, func(omission string, _ int) []int { return omissionFormulas[omission] })
+	chordTones = lo.Without(chordTones, omitted...)
+
 	// The following code generates a voicing with no regard to what the previous chord was.
-	// In the python code, a much longer chunk of code uses an iterative algorithm to find
-	// a combination of octaves for the notes that minimizes the sum of the distances
+	// Voicer finds the combination of octaves for the notes that minimizes the distances
 	// of each note in the new chord to the nearest note in the previous chord.
 
 	voicing := []int{}
@@ -181,139 +323,119 @@ func (c Chord) GetFrequenciesV2() []float64 {
 		voicing = append(voicing, root+48+tone)
 	}
 	for _, extension := range c.Extensions {
-		voicing = append(voicing, root+extensionOctave+extensionFormulas[extension])
+		for _, tone := range extensionTones(extension) {
+			voicing = append(voicing, root+extensionOctave+tone)
+		}
 	}
-	if lowRoot && !slices.Contains(chordTones, 6) && !slices.Contains(chordTones, 8) && !slices.Contains(c.Extensions, "#5") && !slices.Contains(c.Extensions, "b5") {
+	// The doubled root and fifth are left out when omitted, the bass always sounds
+	if lowRoot && !slices.Contains(omitted, 7) && !slices.Contains(chordTones, 6) && !slices.Contains(chordTones, 8) && !slices.Contains(c.Extensions, "#5") && !slices.Contains(c.Extensions, "b5") {
 		voicing = append(voicing, root+43)
 	}
-	voicing = append(voicing, root+48)
+	if !slices.Contains(omitted, 0) {
+		voicing = append(voicing, root+48)
+	}
 	if lowRoot {
 		voicing = append(voicing, bassNote+36)
 		voicing = append(voicing, bassNote+24)
 	}
+	if c.Upper != nil {
+		voicing = append(voicing, c.Upper.voiceAbove(slices.Max(voicing))...)
+	}
 
-	// The chord is now voiced in MIDI note numbers.
-	freqVoicing := []float64{}
-
-	for _, m := range voicing {
-		freqVoicing = append(freqVoicing, math.Pow(2, float64((float64(m)-69.0)/12.0))*440.0)
+	if !c.Constraints.IsZero() {
+		// The constraints keep the first note as the bass
+		slices.Sort(voicing)
 	}
-	return freqVoicing
+
+	// The chord is now voiced in MIDI note numbers.
+	return c.constrain(voicing).Frequencies(c.tuning())
 }
 
 func (c *Chord) applyVoicingRules() {
-	for _, rule := range c.VoicingRules {
-		if rule.Condition(c) {
-			rule.Action(c)
-		}
-	}
+	applyVoicingRules(c, c.VoicingRules)
+	c.keepMelodyTone()
 }
 
+// TraceVoicingRules returns the steps of the voicing rules that change the chord
+// tones, without changing the chord.
+func (c Chord) TraceVoicingRules() []VoicingStep {
+	c.Tones = slices.Clone(c.Tones)
+	return applyVoicingRules(&c, c.VoicingRules)
+}
+
+// WithVoicingRules returns a copy of the chord voiced with the rules of a rule set
+func (c Chord) WithVoicingRules(set *VoicingRuleSet) *Chord {
+	c.VoicingRules = slices.Clone(set.Rules)
+	return &c
+}
+
+// ParseChord parses a chord symbol like ParseChordE, and returns nil when the
+// symbol cannot be parsed.
+//
+// Deprecated: Use ParseChordE to get the parse error, or MustParseChord for
+// chord symbols that are known to be valid.
 func ParseChord(chordStr string) *Chord {
-	finding := "root"
-	chord := &Chord{VoicingRules: defaultVoicingRules}
-	accumulator := ""
-	extensions := []string{}
-	for _, char := range chordStr {
-
-		if finding == "bass" {
-			accumulator += string(char) // string concatenation
-		}
-		if char == '/' { // any part of the chord can suddenly end like this then go to bass
-			switch finding {
-			case "root":
-				chord.Root = accumulator
-			case "quality":
-				chord.Quality = accumulator
-			case "extensions":
-				extensions = append(extensions, accumulator)
-				chord.Extensions = extensions
-			}
-			finding = "bass"
-			accumulator = ""
-		}
-		if finding == "root" {
-			if (!unicode.IsDigit(char) && !unicode.IsLower(char)) || char == 'b' {
-				accumulator += string(char)
-			} else if char == 's' { // sus
-				finding = "extensions"
-				chord.Root = accumulator
-				accumulator = ""
-			} else {
-				finding = "quality"
-				chord.Root = accumulator
-				accumulator = ""
+	chord, _ := ParseChordE(chordStr)
+	return chord
+}
 
-			}
-		}
-		if finding == "quality" {
-			if len(accumulator) > 2 {
-				if accumulator[len(accumulator)-3:] == "add" {
-					chord.Quality = accumulator[:len(accumulator)-3]
-					finding = "extensions"
-					accumulator = ""
-				}
-			}
-			if finding == "quality" {
-				if !slices.Contains([]rune{'#', 'b', '(', 's'}, char) {
-					accumulator += string(char)
-				} else {
-					finding = "extensions"
-					chord.Quality = accumulator
-					accumulator = ""
-				}
-			}
-		}
-		if finding == "extensions" {
-			if slices.Contains([]rune{'#', 'b'}, char) {
-				if accumulator != "" {
-					extensions = append(extensions, accumulator)
-				}
-				accumulator = string(char)
-			} else if char == '(' {
-				if accumulator != "" {
-					extensions = append(extensions, accumulator)
-				}
-				accumulator = ""
-			} else if slices.Contains([]rune{',', ')'}, char) {
-				extensions = append(extensions, accumulator)
-				accumulator = ""
-			} else {
-				accumulator += string(char)
-			}
-		}
-	}
-	switch finding {
-	case "root":
-		chord.Root = accumulator
-	case "quality":
-		chord.Quality = accumulator
-	case "extensions":
-		if accumulator != "" {
-			extensions = append(extensions, accumulator)
-		}
-		chord.Extensions = extensions
-	case "bass": // rare "bass case"
-		chord.BassNote = accumulator
-	}
-	if chord.BassNote == "" {
-		chord.BassNote = chord.Root
+// MustParseChord is like ParseChordE but panics if the chord symbol cannot be
+// parsed. It simplifies the use of chord symbols that are known to be valid, and
+// ParseChordE should be used for symbols given by users.
+func MustParseChord(chordStr string) *Chord {
+	chord, err := ParseChordE(chordStr)
+	if err != nil {
+		panic(err)
 	}
+	return chord
+}
 
-	if strings.Contains(chord.Root, "b") {
-		chord.Root = flatToSharp[chord.Root]
+// ParseChordE parses a chord symbol such as "Cm7b5/Gb" with ParseChordSymbol and
+// builds the corresponding Chord. A *ParseError is returned when the symbol is invalid.
+func ParseChordE(chordStr string) (*Chord, error) {
+	symbol, err := ParseChordSymbol(chordStr)
+	if err != nil {
+		return nil, err
 	}
+	return symbol.Chord(), nil
+}
 
-	if strings.Contains(chord.BassNote, "b") {
-		chord.BassNote = flatToSharp[chord.BassNote]
+// Chord builds the Chord described by the syntax tree
+func (s *ChordSymbol) Chord() *Chord {
+	chord := &Chord{
+		Root:         ParseNote(s.Root),
+		Quality:      s.Quality,
+		BassNote:     ParseNote(s.Root),
+		Extensions:   append(append([]string(nil), s.Alterations...), s.Additions...),
+		Omissions:    s.Omissions,
+		VoicingRules: slices.Clone(defaultVoicingRules),
+	}
+	if s.Bass != "" {
+		chord.BassNote = ParseNote(s.Bass)
 	}
 
-	chord.Tones = append(chord.Tones, 0)
-
-	chord.Tones = append(chord.Tones, chordFormulas[chord.Quality]...)
+	formula := append([]int{0}, semitoneOffsets(DefaultChordRegistry.formula(chord.Quality))...)
+	for _, omission := range chord.Omissions {
+		formula = lo.Without(formula, omissionFormulas[omission]...)
+	}
+	if lo.SomeBy(chord.Extensions, func(extension string) bool { return strings.HasPrefix(extension, "sus") }) {
+		// The suspended tone replaces the third
+		formula = lo.Without(formula, 3, 4)
+	}
+	if lo.Some(chord.Extensions, []string{"alt", "b5", "#5"}) {
+		// The altered fifths replace the natural fifth
+		formula = lo.Without(formula, 7)
+	}
+	chord.Tones = append(chord.Tones, formula...)
 
 	for _, extension := range chord.Extensions {
-		chord.Tones = append(chord.Tones, extensionFormulas[extension])
+		chord.Tones = append(chord.Tones, extensionTones(extension)...)
+	}
+
+	if s.Upper != nil {
+		chord.Upper = s.Upper.Chord()
+		// The upper structure is stacked an octave above the root of the lower chord
+		chord.Tones = append(chord.Tones, chord.upperTones()...)
 	}
 	return chord
 }
@@ -2,14 +2,17 @@ package godio
 
 import (
 	"fmt"
+	"slices"
 	"testing"
+
+	"github.com/samber/lo"
 )
 
 func TestChordToneManipulation(t *testing.T) {
 	chord := Chord{
-		Root:     "C",
+		Root:     ParseNote("C"),
 		Quality:  "maj",
-		BassNote: "C",
+		BassNote: ParseNote("C"),
 	}
 
 	chord.addTone(4)
@@ -35,7 +38,7 @@ func TestChordParsingAndVoicing(t *testing.T) {
 		{"Cmaj7", []string{"C2", "C4", "E4", "G3", "B3"}},
 		{"Cm7", []string{"C2", "C4", "D#4", "G3", "A#3"}},
 		{"Cmmaj7", []string{"C2", "C4", "D#4", "G3", "B3"}},
-		{"Cm7b5", []string{"C2", "D#4", "G3", "A#3", "F#4"}},
+		{"Cm7b5", []string{"C2", "C4", "D#4", "A#3", "F#4"}},
 		{"Cmadd11", []string{"C2", "C4", "D#4", "G3", "F4"}},
 		{"Cmadd#11", []string{"C2", "C4", "D#4", "G3", "F#4"}},
 		{"C9add11", []string{"C2", "E4", "A#3", "D4", "F4"}},
@@ -45,11 +48,18 @@ func TestChordParsingAndVoicing(t *testing.T) {
 		{"F#13", []string{"F#2", "A#3", "E4", "G#3", "D#4"}},
 		{"Cm7/G", []string{"G2", "C4", "D#4", "G3", "A#3"}},
 		{"Cm7/Gb", []string{"F#2", "C4", "D#4", "G3", "A#3"}},
+		{"C(no3)", []string{"C2", "C4", "G3"}},
+		{"C7(omit5)", []string{"C2", "C4", "E4", "A#3"}},
+		{"Cadd2add4", []string{"C2", "E4", "G3", "D4", "F4"}},
+		{"C6/9", []string{"C2", "C4", "E4", "A3", "D4"}},
+		{"D/C7", []string{"C2", "C4", "E4", "G3", "A#3", "D5", "F#5", "A5"}},
+		{"Eb|C", []string{"C2", "C4", "E4", "G3", "D#5", "G5", "A#5"}},
+		{"Ab/C7alt/E", []string{"E2", "E4", "A#3", "C#4", "D#4", "F#4", "G#3", "G#4", "C5", "D#5"}},
 	}
 
 	for i := range parameters {
 		t.Run(fmt.Sprintf("Testing %v", parameters[i].input), func(t *testing.T) {
-			chord := ParseChord(parameters[i].input)
+			chord := MustParseChord(parameters[i].input)
 			frequencies := chord.GetFrequencies()
 			for j, frequency := range frequencies {
 				expectedFrequency := NoteFrequencies[parameters[i].expected[j]]
@@ -60,3 +70,151 @@ func TestChordParsingAndVoicing(t *testing.T) {
 		})
 	}
 }
+
+func TestParseChordErrors(t *testing.T) {
+	parameters := []struct {
+		input    string
+		offset   int
+		token    string
+		expected string
+	}{
+		{"H7", 0, "H", "a root note (A-G)"},
+		{"Cxyz", 1, "xyz", "a chord quality"},
+		{"C7#4", 2, "#4", "a chord extension"},
+		{"C/", 2, "", "a bass note (A-G)"},
+		{"", 0, "", "a root note (A-G)"},
+	}
+
+	for _, p := range parameters {
+		t.Run(fmt.Sprintf("Testing %q", p.input), func(t *testing.T) {
+			chord, err := ParseChordE(p.input)
+			if chord != nil {
+				t.Errorf("Expected no chord, but got %+v", chord)
+			}
+			parseErr, ok := err.(*ParseError)
+			if !ok {
+				t.Fatalf("Expected a *ParseError, but got %v", err)
+			}
+			if parseErr.Offset != p.offset || parseErr.Token != p.token || parseErr.Expected != p.expected {
+				t.Errorf("Expected %q at offset %d (%s), but got %q at offset %d (%s)", p.token, p.offset, p.expected, parseErr.Token, parseErr.Offset, parseErr.Expected)
+			}
+		})
+	}
+}
+
+func TestParseChord(t *testing.T) {
+	if chord := ParseChord("Cm7"); chord == nil || chord.String() != MustParseChord("Cm7").String() {
+		t.Errorf("Expected Cm7, but got %v", chord)
+	}
+	if chord := ParseChord("Cxyz"); chord != nil {
+		t.Errorf("Expected nil for an invalid symbol, but got %v", chord)
+	}
+}
+
+func TestJazzShorthand(t *testing.T) {
+	parameters := []struct {
+		input    string
+		expected string
+	}{
+		{"C-7", "Cm7"},
+		{"CΔ9", "Cmaj9"},
+		{"CΔ", "Cmaj7"},
+		{"C-Δ7", "Cmmaj7"},
+		{"Bø7", "Bm7b5"},
+		{"F#°7", "F#dim7"},
+		{"G+", "Gaug"},
+		{"G+7", "G7#5"},
+		{"B♭mi7", "Bbm7"},
+		{"C7-9", "C7b9"},
+		{"C7+5", "C7#5"},
+		{"C7+9", "C7#9"},
+		{"C9+11", "C9#11"},
+		{"G7alt", "G7(b9,#9,#11,b13,no5)"},
+	}
+
+	for _, p := range parameters {
+		t.Run(fmt.Sprintf("Testing %v", p.input), func(t *testing.T) {
+			chord, err := ParseChordE(p.input)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			expected := MustParseChord(p.expected)
+			if !slices.Equal(chord.PitchClasses(), expected.PitchClasses()) {
+				t.Errorf("Expected pitch classes %v, but got %v", expected.PitchClasses(), chord.PitchClasses())
+			}
+		})
+	}
+}
+
+func TestSuspendedChords(t *testing.T) {
+	parameters := []struct {
+		input    string
+		expected []int
+	}{
+		{"Csus4", []int{0, 5, 7}},
+		{"Csus2", []int{0, 2, 7}},
+		{"C7sus4", []int{0, 5, 7, 10}},
+		{"Cm7sus4", []int{0, 5, 7, 10}},
+		{"C9sus", []int{0, 2, 5, 7, 10}},
+	}
+
+	for _, p := range parameters {
+		t.Run(fmt.Sprintf("Testing %v", p.input), func(t *testing.T) {
+			// The suspended tone replaces the major or minor third
+			classes := MustParseChord(p.input).PitchClasses()
+			if !slices.Equal(classes, p.expected) {
+				t.Errorf("Expected %v, but got %v", p.expected, classes)
+			}
+		})
+	}
+}
+
+func TestPolychordLower(t *testing.T) {
+	parameters := []struct {
+		chord    *Chord
+		expected []int
+	}{
+		{MustParseChord("D/C7"), []int{0, 4, 7, 10}},
+		{MustParseChord("D/C7").WithTopNote(ParsePitch("C6")), []int{4, 7, 10, 0}},
+		{MustParseChord("D/Cm11"), []int{0, 3, 7, 10, 14, -7}},
+	}
+
+	for _, p := range parameters {
+		t.Run(fmt.Sprintf("Testing %v", p.chord), func(t *testing.T) {
+			if lower := p.chord.Lower(); !slices.Equal(lower.Tones, p.expected) {
+				t.Errorf("Expected %v, but got %v", p.expected, lower.Tones)
+			}
+
+			// The upper structure is voiced by every voicing method
+			for _, frequencies := range [][]float64{p.chord.GetFrequencies(), p.chord.GetFrequenciesV2()} {
+				classes := lo.Map(frequencies, func(frequency float64, _ int) int { return mod12(StandardTuning.Note(frequency)) })
+				for _, class := range p.chord.Upper.PitchClasses() {
+					if !slices.Contains(classes, class) {
+						t.Errorf("Expected the pitch class %v of the upper structure in %v", class, classes)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestGetFrequenciesV2Omissions(t *testing.T) {
+	parameters := []struct {
+		input   string
+		omitted int
+	}{
+		{"C7(omit5)", 7},
+		{"C6/9(no5)", 7},
+		{"C(no3)", 4},
+	}
+
+	for _, p := range parameters {
+		t.Run(fmt.Sprintf("Testing %v", p.input), func(t *testing.T) {
+			for _, frequency := range MustParseChord(p.input).GetFrequenciesV2() {
+				if note := StandardTuning.Note(frequency); mod12(note) == p.omitted {
+					t.Errorf("Expected no %v, but got it", PitchFromMIDI(note))
+				}
+			}
+		})
+	}
+}
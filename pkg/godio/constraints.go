@@ -0,0 +1,225 @@
+package godio
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/samber/lo"
+)
+
+// VoicingConstraints limits where the notes of a voicing are placed, such as the
+// range of an instrument. The zero value sets no limits.
+type VoicingConstraints struct {
+	Lowest            int         // Lowest MIDI note, 0 for no limit
+	Highest           int         // Highest MIDI note, 0 for no limit
+	MaxSpan           int         // Largest interval in semitones from the lowest to the highest note, 0 for no limit
+	LowIntervalLimits map[int]int // Lowest MIDI note of the lower of two adjacent notes by the interval in semitones between them
+	TargetTopNote     int         // MIDI note the highest voice is moved closest to by octaves, 0 for none
+	TopNote           int         // MIDI note of a melody that is always the highest voice, 0 for none
+}
+
+// DefaultLowIntervalLimits are the usual low interval limits, below which the
+// interval between two adjacent notes sounds muddy, such as a major third whose
+// lower note is below Bb2. Intervals missing from the map have no limit.
+var DefaultLowIntervalLimits = map[int]int{
+	1:  ParsePitch("E3").MIDI(),
+	2:  ParsePitch("Eb3").MIDI(),
+	3:  ParsePitch("C3").MIDI(),
+	4:  ParsePitch("Bb2").MIDI(),
+	5:  ParsePitch("A2").MIDI(),
+	6:  ParsePitch("B2").MIDI(),
+	7:  ParsePitch("Bb1").MIDI(),
+	8:  ParsePitch("G2").MIDI(),
+	9:  ParsePitch("F2").MIDI(),
+	10: ParsePitch("F2").MIDI(),
+	11: ParsePitch("F2").MIDI(),
+	13: ParsePitch("E2").MIDI(),
+	14: ParsePitch("Eb2").MIDI(),
+	15: ParsePitch("G1").MIDI(),
+	16: ParsePitch("Bb1").MIDI(),
+}
+
+// InstrumentConstraints are the constraints of the sounding range of common instruments
+var InstrumentConstraints = map[string]VoicingConstraints{
+	"piano": {
+		Lowest:            ParsePitch("A0").MIDI(),
+		Highest:           ParsePitch("C8").MIDI(),
+		LowIntervalLimits: DefaultLowIntervalLimits,
+	},
+	"guitar": {
+		Lowest:  ParsePitch("E2").MIDI(),
+		Highest: ParsePitch("B5").MIDI(),
+		MaxSpan: 24,
+	},
+	"vibraphone": {
+		Lowest:  ParsePitch("F3").MIDI(),
+		Highest: ParsePitch("F6").MIDI(),
+	},
+	"brass": {
+		Lowest:            ParsePitch("E2").MIDI(),
+		Highest:           ParsePitch("C6").MIDI(),
+		LowIntervalLimits: DefaultLowIntervalLimits,
+	},
+}
+
+// ParseInstrumentConstraints returns the constraints of an instrument of InstrumentConstraints
+func ParseInstrumentConstraints(name string) (VoicingConstraints, error) {
+	constraints, ok := InstrumentConstraints[strings.ToLower(name)]
+	if !ok {
+		names := lo.Keys(InstrumentConstraints)
+		slices.Sort(names)
+		return VoicingConstraints{}, fmt.Errorf("unknown instrument %q, expected one of %s", name, strings.Join(names, ", "))
+	}
+	return constraints, nil
+}
+
+// IsZero reports whether the constraints set no limits
+func (vc VoicingConstraints) IsZero() bool {
+	return vc.Lowest == 0 && vc.Highest == 0 && vc.MaxSpan == 0 && len(vc.LowIntervalLimits) == 0 && vc.TargetTopNote == 0 && vc.TopNote == 0
+}
+
+// Allows reports whether the notes, in any order, respect the constraints. The
+// target top note is a preference and is not checked.
+func (vc VoicingConstraints) Allows(notes []int) bool {
+	sorted := slices.Clone(notes)
+	slices.Sort(sorted)
+	if len(sorted) == 0 {
+		return true
+	}
+	lowest, highest := sorted[0], sorted[len(sorted)-1]
+	if (vc.Lowest > 0 && lowest < vc.Lowest) || (vc.Highest > 0 && highest > vc.Highest) {
+		return false
+	}
+	if vc.MaxSpan > 0 && highest-lowest > vc.MaxSpan {
+		return false
+	}
+	if vc.TopNote > 0 && highest != vc.TopNote {
+		return false
+	}
+	return vc.lowIntervalViolation(sorted) < 0
+}
+
+// Apply moves notes of the voicing by octaves until it respects the constraints,
+// and returns the notes in ascending order without duplicates. The first note is
+// the bass, which is moved within range and stays the lowest note: the upper
+// voices are kept above it. The top note is first placed above the other notes,
+// or else the upper voices are moved together so that the highest one is closest
+// to the target top note. Each note is then moved within range, below the top
+// note. Adjacent notes too low for their interval are spread by raising the upper
+// one, and the voicing is narrowed to the maximum span by lowering its highest
+// notes, or raising its lowest upper ones under a top note. Notes that cannot be
+// moved without leaving the range are left in place, so the result may not
+// respect every limit, except for upper voices that stay above the top note,
+// which are left out so that the melody is always the highest voice.
+func (vc VoicingConstraints) Apply(voicing Voicing) Voicing {
+	if len(voicing) == 0 {
+		return Voicing{}
+	}
+	bass := voicing[0]
+	upper := sortedUnique(slices.Clone(voicing[1:]))
+
+	ceiling := vc.Highest
+	if vc.TopNote > 0 {
+		upper = placeTopNote(upper, vc.TopNote)
+		ceiling = vc.TopNote
+	} else if vc.TargetTopNote > 0 && len(upper) > 0 {
+		shift := 12 * roundDiv(vc.TargetTopNote-upper[len(upper)-1], 12)
+		for i := range upper {
+			upper[i] += shift
+		}
+	}
+
+	bass = vc.withinRange(bass, ceiling)
+	for i, note := range upper {
+		upper[i] = vc.withinRange(note, ceiling)
+	}
+	notes := vc.aboveBass(bass, upper, ceiling)
+
+	// Each raise moves a note up an octave within range, so there are at most a few per note
+	for attempts := 0; attempts < 4*len(notes); attempts++ {
+		i := vc.lowIntervalViolation(notes)
+		if i < 0 || (ceiling > 0 && notes[i+1]+12 > ceiling) {
+			break
+		}
+		notes[i+1] += 12
+		notes = vc.aboveBass(notes[0], notes[1:], ceiling)
+	}
+
+	for vc.MaxSpan > 0 && len(notes) > 1 && notes[len(notes)-1]-notes[0] > vc.MaxSpan {
+		if vc.TopNote > 0 {
+			if len(notes) < 3 || notes[1]+12 >= vc.TopNote {
+				break
+			}
+			notes[1] += 12
+			notes = vc.aboveBass(notes[0], notes[1:], ceiling)
+			continue
+		}
+		top := notes[len(notes)-1] - 12
+		if top <= notes[0] || (vc.Lowest > 0 && top < vc.Lowest) {
+			break
+		}
+		notes[len(notes)-1] = top
+		notes = vc.aboveBass(notes[0], notes[1:], ceiling)
+	}
+
+	if vc.TopNote > 0 {
+		// Notes that could not be lowered under the melody, as the bass could not go lower
+		notes = append(notes[:1], lo.Filter(notes[1:], func(note int, _ int) bool { return note <= vc.TopNote })...)
+	}
+	return notes
+}
+
+// withinRange moves a note by octaves above the lowest note and, when it stays
+// within range, below the ceiling
+func (vc VoicingConstraints) withinRange(note int, ceiling int) int {
+	for vc.Lowest > 0 && note < vc.Lowest {
+		note += 12
+	}
+	for ceiling > 0 && note > ceiling && note-12 >= vc.Lowest {
+		note -= 12
+	}
+	return note
+}
+
+// aboveBass returns the bass followed by the upper notes in ascending order
+// without duplicates, with the upper notes not above the bass raised by octaves.
+// When that would take them above the ceiling, the bass is lowered instead
+// if it stays within range.
+func (vc VoicingConstraints) aboveBass(bass int, upper []int, ceiling int) []int {
+	upper = slices.Clone(upper)
+	for i, note := range upper {
+		for note <= bass {
+			if ceiling > 0 && note+12 > ceiling && bass-12 >= vc.Lowest {
+				bass -= 12
+				continue
+			}
+			note += 12
+		}
+		upper[i] = note
+	}
+	return append([]int{bass}, sortedUnique(upper)...)
+}
+
+// lowIntervalViolation returns the index of the lower of the first two adjacent
+// sorted notes that are too low for their interval, or -1 if there is none.
+func (vc VoicingConstraints) lowIntervalViolation(sorted []int) int {
+	for i := 1; i < len(sorted); i++ {
+		limit, ok := vc.LowIntervalLimits[sorted[i]-sorted[i-1]]
+		if ok && sorted[i-1] < limit {
+			return i - 1
+		}
+	}
+	return -1
+}
+
+// sortedUnique sorts notes in place and removes the duplicates
+func sortedUnique(notes []int) []int {
+	slices.Sort(notes)
+	return slices.Compact(notes)
+}
+
+// roundDiv divides rounding to the nearest integer, halves towards negative infinity
+func roundDiv(a int, b int) int {
+	return floorDiv(2*a+b-1, 2*b)
+}
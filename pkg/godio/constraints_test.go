@@ -0,0 +1,108 @@
+package godio
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/samber/lo"
+)
+
+// pitchNames returns the names of MIDI notes separated by spaces
+func pitchNames(notes []int) string {
+	return strings.Join(lo.Map(notes, func(note int, _ int) string { return PitchFromMIDI(note).String() }), " ")
+}
+
+func TestVoicingConstraints(t *testing.T) {
+	parameters := []struct {
+		name        string
+		constraints VoicingConstraints
+		voicing     Voicing
+		expected    string
+	}{
+		{
+			"low interval limits",
+			VoicingConstraints{LowIntervalLimits: DefaultLowIntervalLimits},
+			Voicing{ParsePitch("C2").MIDI(), ParsePitch("E2").MIDI(), ParsePitch("G2").MIDI(), ParsePitch("C3").MIDI()},
+			"C2 G2 E3 C4",
+		},
+		{
+			"range",
+			InstrumentConstraints["vibraphone"],
+			MustParseChord("Cmaj7").Voice(VoicingDrop2),
+			"C4 E4 G4 B4 C5",
+		},
+		{
+			"slash bass",
+			InstrumentConstraints["vibraphone"],
+			MustParseChord("Cmaj7/E").Voice(VoicingClose),
+			"E4 G4 B4 C5 E5",
+		},
+		{
+			"bass under a top note",
+			VoicingConstraints{TopNote: ParsePitch("E4").MIDI()},
+			Voicing{ParsePitch("G4").MIDI(), ParsePitch("C4").MIDI(), ParsePitch("E5").MIDI()},
+			"G3 C4 E4",
+		},
+		{
+			"maximum span",
+			VoicingConstraints{MaxSpan: 12},
+			MustParseChord("Cmaj7").Voice(VoicingSpread),
+			"C3 E3 G3 B3",
+		},
+		{
+			"target top note",
+			VoicingConstraints{TargetTopNote: ParsePitch("A5").MIDI()},
+			MustParseChord("Cmaj7").Voice(VoicingClose),
+			"C3 C5 E5 G5 B5",
+		},
+	}
+
+	for _, p := range parameters {
+		t.Run(fmt.Sprintf("Testing %v", p.name), func(t *testing.T) {
+			voicing := p.constraints.Apply(p.voicing)
+			if names := pitchNames(voicing); names != p.expected {
+				t.Errorf("Expected %v, but got %v", p.expected, names)
+			}
+			if p.constraints.TargetTopNote == 0 && !p.constraints.Allows(voicing) {
+				t.Errorf("Expected %v to respect the constraints", pitchNames(voicing))
+			}
+		})
+	}
+}
+
+func TestChordConstraints(t *testing.T) {
+	guitar := InstrumentConstraints["guitar"]
+	for _, symbol := range []string{"C", "Cmaj7", "G13", "F#m7b5"} {
+		t.Run(fmt.Sprintf("Testing %v", symbol), func(t *testing.T) {
+			chord := MustParseChord(symbol)
+			chord.Constraints = guitar
+			for _, style := range []VoicingStyle{VoicingClose, VoicingDrop2, VoicingSpread} {
+				if voicing := chord.Voice(style); !guitar.Allows(voicing) {
+					t.Errorf("Expected the %v voicing in the range of a guitar, but got %v", style, pitchNames(voicing))
+				}
+			}
+
+			vibraphone := InstrumentConstraints["vibraphone"]
+			chord.Constraints = vibraphone
+			for _, frequency := range append(chord.GetFrequencies(), chord.GetFrequenciesV2()...) {
+				note := StandardTuning.Note(frequency)
+				if note < vibraphone.Lowest || note > vibraphone.Highest {
+					t.Errorf("Expected the notes in the range of a vibraphone, but got %v", PitchFromMIDI(note))
+				}
+			}
+		})
+	}
+}
+
+func TestVoicerConstraints(t *testing.T) {
+	voicer := NewVoicer()
+	voicer.Constraints = VoicingConstraints{TargetTopNote: ParsePitch("C5").MIDI()}
+	chords := []*Chord{MustParseChord("Dm7"), MustParseChord("G7"), MustParseChord("Cmaj7")}
+	expected := []string{"D3 D4 F4 A4 C5", "G2 D4 F4 G4 B4", "C3 E4 G4 B4 C5"}
+	for i, voicing := range voicer.Voice(chords) {
+		if names := pitchNames(voicing); names != expected[i] {
+			t.Errorf("Expected %v, but got %v", expected[i], names)
+		}
+	}
+}
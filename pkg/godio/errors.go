@@ -0,0 +1,18 @@
+package godio
+
+import "fmt"
+
+// ParseError describes why a chord symbol could not be parsed
+type ParseError struct {
+	Input    string // The full chord symbol being parsed
+	Offset   int    // Byte offset of the offending token in Input
+	Token    string // The offending token, empty at the end of the input
+	Expected string // Description of what was expected instead
+}
+
+func (e *ParseError) Error() string {
+	if e.Token == "" {
+		return fmt.Sprintf("invalid chord %q: unexpected end of input at offset %d, expected %s", e.Input, e.Offset, e.Expected)
+	}
+	return fmt.Sprintf("invalid chord %q: unexpected %q at offset %d, expected %s", e.Input, e.Token, e.Offset, e.Expected)
+}
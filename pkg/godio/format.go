@@ -0,0 +1,210 @@
+package godio
+
+import (
+	"strings"
+)
+
+type NotationStyle string
+
+const (
+	NotationPop     NotationStyle = "Pop"     // Cmaj7, Cm7, Cdim7, C7b9
+	NotationJazz    NotationStyle = "Jazz"    // CΔ7, C-7, C°7, C7(b9#11)
+	NotationBerklee NotationStyle = "Berklee" // Cmaj7, C-7, Co7, C7(b9, #11)
+	NotationVerbose NotationStyle = "Verbose" // C major seventh
+)
+
+// qualityNotations spells each key of chordFormulas in every notation style.
+// Half-diminished chords are written with ø in every style because "m7b5" is
+// parsed as a minor seventh with an added flat fifth.
+var qualityNotations = map[NotationStyle]map[string]string{
+	NotationPop: {
+		"maj":     "",
+		"mmaj7":   "mmaj7",
+		"minmaj7": "mmaj7",
+	},
+	NotationJazz: {
+		"maj":       "",
+		"aug":       "+",
+		"dim":       "°",
+		"dim7":      "°7",
+		"m":         "-",
+		"m7":        "-7",
+		"maj7":      "Δ7",
+		"m9":        "-9",
+		"maj9":      "Δ9",
+		"m11":       "-11",
+		"m6":        "-6",
+		"m69":       "-69",
+		"mmaj7":     "-Δ7",
+		"minmaj7":   "-Δ7",
+		"ø7":        "ø",
+		"aug7":      "+7",
+		"augmaj7":   "+Δ7",
+		"dim(maj7)": "°(Δ7)",
+	},
+	NotationBerklee: {
+		"maj":       "",
+		"aug":       "+",
+		"dim":       "o",
+		"dim7":      "o7",
+		"m":         "-",
+		"m7":        "-7",
+		"m9":        "-9",
+		"m11":       "-11",
+		"m6":        "-6",
+		"m69":       "-69",
+		"mmaj7":     "-maj7",
+		"minmaj7":   "-maj7",
+		"aug7":      "+7",
+		"augmaj7":   "+maj7",
+		"dim(maj7)": "o(maj7)",
+	},
+	NotationVerbose: {
+		"":          "major",
+		"maj":       "major",
+		"aug":       "augmented",
+		"dim":       "diminished",
+		"dim7":      "diminished seventh",
+		"m":         "minor",
+		"m7":        "minor seventh",
+		"7":         "dominant seventh",
+		"maj7":      "major seventh",
+		"9":         "dominant ninth",
+		"m9":        "minor ninth",
+		"maj9":      "major ninth",
+		"13":        "dominant thirteenth",
+		"m11":       "minor eleventh",
+		"6":         "major sixth",
+		"m6":        "minor sixth",
+		"69":        "six-nine",
+		"m69":       "minor six-nine",
+		"mmaj7":     "minor major seventh",
+		"minmaj7":   "minor major seventh",
+		"ø7":        "half-diminished seventh",
+		"aug7":      "augmented seventh",
+		"augmaj7":   "augmented major seventh",
+		"dim(maj7)": "diminished major seventh",
+		"7sus4b9":   "dominant seventh suspended fourth flat ninth",
+		"5":         "power chord",
+	},
+}
+
+// verboseDegrees names the chord degrees used by extensions and omissions in the verbose style
+var verboseDegrees = map[string]string{
+	"1":  "unison",
+	"2":  "second",
+	"3":  "third",
+	"4":  "fourth",
+	"5":  "fifth",
+	"7":  "seventh",
+	"9":  "ninth",
+	"11": "eleventh",
+	"13": "thirteenth",
+	"15": "fifteenth",
+}
+
+// String returns the chord symbol in NotationPop
+func (c Chord) String() string {
+	return c.Format(NotationPop)
+}
+
+// Format returns the chord symbol written in the given notation style.
+// Every style except NotationVerbose can be parsed back by ParseChordE into a
+// chord with the same pitch classes.
+func (c Chord) Format(style NotationStyle) string {
+	if style == NotationVerbose {
+		return c.formatVerbose()
+	}
+
+	var sb strings.Builder
+	if c.Upper != nil {
+		sb.WriteString(c.Upper.Format(style))
+		sb.WriteString("|")
+	}
+	sb.WriteString(c.Root.String())
+	quality := formatQuality(c.Quality, style)
+	sb.WriteString(quality)
+
+	var attached, grouped []string
+	for _, extension := range c.Extensions {
+		switch {
+		case strings.HasPrefix(extension, "sus") || extension == "alt":
+			attached = append(attached, extension)
+		case style == NotationPop && (extension[0] == '#' || extension[0] == 'b') && quality != "":
+			// Without a quality the accidental would be read as part of the root
+			attached = append(attached, extension)
+		case style == NotationPop && extension[0] >= '0' && extension[0] <= '9':
+			attached = append(attached, "add"+extension)
+		default:
+			grouped = append(grouped, extension)
+		}
+	}
+	for _, omission := range c.Omissions {
+		grouped = append(grouped, "no"+omission)
+	}
+	sb.WriteString(strings.Join(attached, ""))
+	if len(grouped) > 0 {
+		sb.WriteString("(")
+		for i, item := range grouped {
+			switch {
+			case i == 0:
+			case style == NotationBerklee:
+				sb.WriteString(", ")
+			case style == NotationPop || item[0] != '#' && item[0] != 'b':
+				// Jazz only runs alterations together, consecutive degrees would read as a single number
+				sb.WriteString(",")
+			}
+			sb.WriteString(item)
+		}
+		sb.WriteString(")")
+	}
+
+	if !c.BassNote.IsZero() && c.BassNote != c.Root {
+		sb.WriteString("/" + c.BassNote.String())
+	}
+	return sb.String()
+}
+
+// formatQuality spells a chordFormulas key in the given notation style
+func formatQuality(quality string, style NotationStyle) string {
+	if notation, ok := qualityNotations[style][quality]; ok {
+		return notation
+	}
+	return quality
+}
+
+func (c Chord) formatVerbose() string {
+	words := []string{c.Root.String(), formatQuality(c.Quality, NotationVerbose)}
+	for _, extension := range c.Extensions {
+		words = append(words, formatVerboseExtension(extension))
+	}
+	for _, omission := range c.Omissions {
+		words = append(words, "no "+verboseDegrees[omission])
+	}
+	if !c.BassNote.IsZero() && c.BassNote != c.Root {
+		words = append(words, "over", c.BassNote.String())
+	}
+	if c.Upper != nil {
+		words = append([]string{c.Upper.formatVerbose(), "over"}, words...)
+	}
+	return strings.Join(words, " ")
+}
+
+func formatVerboseExtension(extension string) string {
+	switch {
+	case extension == "sus":
+		return "suspended fourth"
+	case strings.HasPrefix(extension, "sus"):
+		return "suspended " + verboseDegrees[extension[3:]]
+	case extension == "maj7":
+		return "major seventh"
+	case extension == "alt":
+		return "altered"
+	case extension[0] == '#':
+		return "sharp " + verboseDegrees[extension[1:]]
+	case extension[0] == 'b':
+		return "flat " + verboseDegrees[extension[1:]]
+	default:
+		return "added " + verboseDegrees[extension]
+	}
+}
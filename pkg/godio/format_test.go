@@ -0,0 +1,52 @@
+package godio
+
+import (
+	"fmt"
+	"slices"
+	"testing"
+)
+
+func TestChordFormat(t *testing.T) {
+	parameters := []struct {
+		input   string
+		pop     string
+		jazz    string
+		berklee string
+		verbose string
+	}{
+		{"Cmaj7", "Cmaj7", "CΔ7", "Cmaj7", "C major seventh"},
+		{"Cm7", "Cm7", "C-7", "C-7", "C minor seventh"},
+		{"Cø7", "Cø7", "Cø", "Cø7", "C half-diminished seventh"},
+		{"Cdim7", "Cdim7", "C°7", "Co7", "C diminished seventh"},
+		{"Caug", "Caug", "C+", "C+", "C augmented"},
+		{"Cm7b5/Gb", "Cm7b5/Gb", "C-7(b5)/Gb", "C-7(b5)/Gb", "C minor seventh flat fifth over Gb"},
+		{"C7(b9,#11)", "C7b9#11", "C7(b9#11)", "C7(b9, #11)", "C dominant seventh flat ninth sharp eleventh"},
+		{"Cmadd9", "Cmadd9", "C-(9)", "C-(9)", "C minor added ninth"},
+		{"C(#11)", "C(#11)", "C(#11)", "C(#11)", "C major sharp eleventh"},
+		{"C7sus4(13,no5)", "C7sus4add13(no5)", "C7sus4(13,no5)", "C7sus4(13, no5)", "C dominant seventh suspended fourth added thirteenth no fifth"},
+		{"Eb|C7", "Eb|C7", "Eb|C7", "Eb|C7", "Eb major over C dominant seventh"},
+		{"Cmaj7(no3)", "Cmaj7(no3)", "CΔ7(no3)", "Cmaj7(no3)", "C major seventh no third"},
+	}
+
+	for _, p := range parameters {
+		t.Run(fmt.Sprintf("Testing %v", p.input), func(t *testing.T) {
+			chord := MustParseChord(p.input)
+			for style, expected := range map[NotationStyle]string{NotationPop: p.pop, NotationJazz: p.jazz, NotationBerklee: p.berklee, NotationVerbose: p.verbose} {
+				formatted := chord.Format(style)
+				if formatted != expected {
+					t.Errorf("Expected %s in %s style, but got %s", expected, style, formatted)
+				}
+				if style == NotationVerbose {
+					continue
+				}
+				reparsed, err := ParseChordE(formatted)
+				if err != nil {
+					t.Fatalf("Could not parse %s back: %v", formatted, err)
+				}
+				if !slices.Equal(reparsed.PitchClasses(), chord.PitchClasses()) {
+					t.Errorf("Expected pitch classes %v for %s, but got %v", chord.PitchClasses(), formatted, reparsed.PitchClasses())
+				}
+			}
+		})
+	}
+}
@@ -11,8 +11,11 @@ import (
 )
 
 const (
-	sampleRate = 44100
-	volume     = 0.8
+	defaultSampleRate = 44100
+	defaultVolume     = 0.8
+	minSampleRate     = 22050
+	maxSampleRate     = 192000
+	maxChannels       = 8
 )
 
 type Waveform string
@@ -32,21 +35,147 @@ type ADSREnvelope struct {
 	Release int     // Duration of the release phase in milliseconds
 }
 
-// SoundBuffer is a buffer for sound data
+// SampleFormat is the encoding of the samples of the WAV files written by SoundBuffer
+type SampleFormat string
+
+const (
+	SampleFormatInt16   SampleFormat = "int16"
+	SampleFormatInt24   SampleFormat = "int24"
+	SampleFormatInt32   SampleFormat = "int32"
+	SampleFormatFloat32 SampleFormat = "float32"
+)
+
+// sampleFormatBitDepths are the bits per sample of each sample format
+var sampleFormatBitDepths = map[SampleFormat]int{
+	SampleFormatInt16:   16,
+	SampleFormatInt24:   24,
+	SampleFormatInt32:   32,
+	SampleFormatFloat32: 32,
+}
+
+// WAV audio formats of the fmt chunk
+const (
+	wavFormatPCM       = 1
+	wavFormatIEEEFloat = 3
+)
+
+// SoundBuffer is a buffer for sound data. Samples are kept between -1 and 1
+// and encoded in the sample format when written.
 type SoundBuffer struct {
-	buffers [][]int
+	buffers      [][]float64
+	sampleRate   int
+	sampleFormat SampleFormat
+	channels     int
+	volume       float64
 }
 
-// NewSoundBuffer creates a new SoundBuffer
-func NewSoundBuffer() *SoundBuffer {
-	return &SoundBuffer{}
+// Option configures a SoundBuffer created by NewSoundBuffer
+type Option func(*SoundBuffer) error
+
+// WithSampleRate sets the number of samples per second, from 22050 to 192000
+func WithSampleRate(rate int) Option {
+	return func(sb *SoundBuffer) error {
+		if rate < minSampleRate || rate > maxSampleRate {
+			return fmt.Errorf("invalid sample rate %d, expected %d to %d Hz", rate, minSampleRate, maxSampleRate)
+		}
+		sb.sampleRate = rate
+		return nil
+	}
+}
+
+// WithSampleFormat sets the encoding of the samples, 16-bit integers by default
+func WithSampleFormat(format SampleFormat) Option {
+	return func(sb *SoundBuffer) error {
+		if _, ok := sampleFormatBitDepths[format]; !ok {
+			return fmt.Errorf("invalid sample format %q, expected int16, int24, int32 or float32", format)
+		}
+		sb.sampleFormat = format
+		return nil
+	}
+}
+
+// WithChannels sets the number of channels, from 1 to 8, each playing the same sound
+func WithChannels(channels int) Option {
+	return func(sb *SoundBuffer) error {
+		if channels < 1 || channels > maxChannels {
+			return fmt.Errorf("invalid channel count %d, expected 1 to %d", channels, maxChannels)
+		}
+		sb.channels = channels
+		return nil
+	}
+}
+
+// WithVolume sets the amplitude of the generated sounds, from 0 to 1
+func WithVolume(volume float64) Option {
+	return func(sb *SoundBuffer) error {
+		if volume < 0 || volume > 1 {
+			return fmt.Errorf("invalid volume %v, expected 0 to 1", volume)
+		}
+		sb.volume = volume
+		return nil
+	}
+}
+
+// NewSoundBuffer creates a new SoundBuffer like NewSoundBufferE, and panics if an option is invalid
+func NewSoundBuffer(opts ...Option) *SoundBuffer {
+	sb, err := NewSoundBufferE(opts...)
+	if err != nil {
+		panic(err)
+	}
+	return sb
+}
+
+// NewSoundBufferE creates a new SoundBuffer, by default of 16-bit mono samples
+// at 44.1 kHz, configured by the options
+func NewSoundBufferE(opts ...Option) (*SoundBuffer, error) {
+	sb := &SoundBuffer{
+		sampleRate:   defaultSampleRate,
+		sampleFormat: SampleFormatInt16,
+		channels:     1,
+		volume:       defaultVolume,
+	}
+	for _, opt := range opts {
+		if err := opt(sb); err != nil {
+			return nil, err
+		}
+	}
+	return sb, nil
+}
+
+// SampleRate returns the number of samples per second
+func (sb *SoundBuffer) SampleRate() int {
+	return sb.sampleRate
+}
+
+// SampleFormat returns the encoding of the samples
+func (sb *SoundBuffer) SampleFormat() SampleFormat {
+	return sb.sampleFormat
+}
+
+// Channels returns the number of channels
+func (sb *SoundBuffer) Channels() int {
+	return sb.channels
+}
+
+// Len returns the number of samples of each channel
+func (sb *SoundBuffer) Len() int {
+	length := 0
+	for _, buffer := range sb.buffers {
+		length += len(buffer)
+	}
+	return length
+}
+
+// samples returns the number of samples in a number of milliseconds
+func (sb *SoundBuffer) samples(milliseconds int) int {
+	return milliseconds * sb.sampleRate / 1000
 }
 
 // ApplyADSR applies the ADSR envelope to a buffer
 func (sb *SoundBuffer) ApplyADSR(env ADSREnvelope) {
-	attackLength := (env.Attack * sampleRate) / 1000
-	decayLength := (env.Decay * sampleRate) / 1000
-	releaseLength := (env.Release * sampleRate) / 1000
+	attackLength := sb.samples(env.Attack)
+	decayLength := sb.samples(env.Decay)
+	releaseLength := sb.samples(env.Release)
 
 	for _, buffer := range sb.buffers {
 		totalLength := len(buffer)
@@ -63,15 +192,22 @@ func (sb *SoundBuffer) ApplyADSR(env ADSREnvelope) {
 			case i < totalLength:
 				amplitude = env.Sustain * (1 - float64(i-attackLength-decayLength-sustainLength)/float64(releaseLength))
 			}
-			buffer[i] = int(float64(buffer[i]) * amplitude)
+			buffer[i] *= amplitude
 		}
 	}
 }
 
-// Write writes the buffer to a seekable writer
+// Write writes the buffer to a seekable writer as a WAV file in the sample rate,
+// sample format and channel count of the buffer
 func (sb *SoundBuffer) Write(seeker io.WriteSeeker) error {
-	intBuf := &audio.IntBuffer{Data: sb.combineBuffers(), Format: &audio.Format{SampleRate: sampleRate, NumChannels: 1}}
-	encoder := wav.NewEncoder(seeker, sampleRate, 16, 1, 1)
+	bitDepth := sampleFormatBitDepths[sb.sampleFormat]
+	audioFormat := wavFormatPCM
+	if sb.sampleFormat == SampleFormatFloat32 {
+		audioFormat = wavFormatIEEEFloat
+	}
+
+	intBuf := &audio.IntBuffer{Data: sb.encode(bitDepth), Format: &audio.Format{SampleRate: sb.sampleRate, NumChannels: sb.channels}, SourceBitDepth: bitDepth}
+	encoder := wav.NewEncoder(seeker, sb.sampleRate, bitDepth, sb.channels, audioFormat)
 	if err := encoder.Write(intBuf); err != nil {
 		return fmt.Errorf("error writing buffer to wav: %v", err)
 	}
@@ -86,14 +222,36 @@ func (sb *SoundBuffer) Write(seeker io.WriteSeeker) error {
 	return nil
 }
 
+// encode returns the samples interleaved for each channel, as integers of a
+// bit depth or as the bits of 32-bit floats
+func (sb *SoundBuffer) encode(bitDepth int) []int {
+	samples := sb.combineBuffers()
+	data := make([]int, 0, len(samples)*sb.channels)
+	amplitude := float64(int(1)<<(bitDepth-1) - 1)
+	for _, sample := range samples {
+		// Clip the sample rather than letting the integers wrap around
+		sample = max(-1, min(1, sample))
+		var value int
+		if sb.sampleFormat == SampleFormatFloat32 {
+			value = int(int32(math.Float32bits(float32(sample))))
+		} else {
+			value = int(amplitude * sample)
+		}
+		for channel := 0; channel < sb.channels; channel++ {
+			data = append(data, value)
+		}
+	}
+	return data
+}
+
 // AppendNote appends a note to a SoundBuffer
 func (sb *SoundBuffer) AppendNote(frequency float64, durationSec float64, waveform Waveform) {
-	numSamples := int(float64(sampleRate) * durationSec)
-	buf := make([]int, numSamples)
+	numSamples := int(float64(sb.sampleRate) * durationSec)
+	buf := make([]float64, numSamples)
 
 	for i := 0; i < numSamples; i++ {
 		var sample float64
-		t := float64(i) / float64(sampleRate)
+		t := float64(i) / float64(sb.sampleRate)
 
 		switch waveform {
 		case WaveformSine:
@@ -110,14 +268,14 @@ func (sb *SoundBuffer) AppendNote(frequency float64, durationSec float64, wavefo
 			sample = 2.0*math.Abs(2.0*(t*frequency-math.Floor(t*frequency+0.5))) - 1.0
 		}
 
-		buf[i] = int(volume * 32767 * sample)
+		buf[i] = sb.volume * sample
 	}
 	sb.buffers = append(sb.buffers, buf)
 }
 
 // AppendChord append a chord buffer for a given set of frequencies and waveform type.
 func (sb *SoundBuffer) AppendChord(frequencies []float64, durationSec float64, waveform Waveform) {
-	numSamples := int(float64(sampleRate) * durationSec)
+	numSamples := int(float64(sb.sampleRate) * durationSec)
 	// Create a buffer for each note in the chord
 	buffers := make([][]float64, len(frequencies))
 
@@ -125,7 +283,7 @@ func (sb *SoundBuffer) AppendChord(frequencies []float64, durationSec float64, w
 	for i, freq := range frequencies {
 		buffers[i] = make([]float64, numSamples)
 		for j := 0; j < numSamples; j++ {
-			t := float64(j) / float64(sampleRate)
+			t := float64(j) / float64(sb.sampleRate)
 			// Choose waveform type
 			switch waveform {
 			case WaveformSine:
@@ -145,7 +303,7 @@ func (sb *SoundBuffer) AppendChord(frequencies []float64, durationSec float64, w
 	}
 
 	// Mix the buffers together
-	chordBuffer := make([]int, numSamples)
+	chordBuffer := make([]float64, numSamples)
 	for i := 0; i < numSamples; i++ {
 		var sample float64
 		for _, buffer := range buffers {
@@ -154,15 +312,15 @@ func (sb *SoundBuffer) AppendChord(frequencies []float64, durationSec float64, w
 		// Normalize the sample to prevent clipping
 		sample = sample / float64(len(buffers))
 
-		chordBuffer[i] = int(volume * 32767 * sample)
+		chordBuffer[i] = sb.volume * sample
 	}
 
 	sb.buffers = append(sb.buffers, chordBuffer)
 }
 
 // combineBuffers combines the buffers in a SoundBuffer into a single buffer
-func (sb *SoundBuffer) combineBuffers() []int {
-	var combinedBuffer []int
+func (sb *SoundBuffer) combineBuffers() []float64 {
+	var combinedBuffer []float64
 	for _, buffer := range sb.buffers {
 		if len(combinedBuffer) == 0 {
 			combinedBuffer = buffer
@@ -180,14 +338,14 @@ type StrumParams struct {
 }
 
 func (sb *SoundBuffer) AppendChordWithStrum(frequencies []float64, durationSec float64, waveform Waveform, strumParams StrumParams, env ADSREnvelope) {
-	numSamples := int(float64(sampleRate) * durationSec)
-	strumSamples := (strumParams.Duration * sampleRate) / 1000
+	numSamples := int(float64(sb.sampleRate) * durationSec)
+	strumSamples := sb.samples(strumParams.Duration)
 
-	attackSamples := (env.Attack * sampleRate) / 1000
-	decaySamples := (env.Decay * sampleRate) / 1000
-	releaseSamples := (env.Release * sampleRate) / 1000
+	attackSamples := sb.samples(env.Attack)
+	decaySamples := sb.samples(env.Decay)
+	releaseSamples := sb.samples(env.Release)
 
-	finalBuffer := make([]int, numSamples)
+	finalBuffer := make([]float64, numSamples)
 
 	for i, freq := range frequencies {
 		baseDelay := (strumSamples * i) / len(frequencies)
@@ -199,7 +357,7 @@ func (sb *SoundBuffer) AppendChordWithStrum(frequencies []float64, durationSec f
 
 		noteBuffer := make([]float64, numSamples)
 		for j := delay; j < numSamples; j++ {
-			t := float64(j-delay) / float64(sampleRate)
+			t := float64(j-delay) / float64(sb.sampleRate)
 
 			// Generate base waveform
 			var sample float64
@@ -237,7 +395,7 @@ func (sb *SoundBuffer) AppendChordWithStrum(frequencies []float64, durationSec f
 
 		// Mix into final buffer
 		for j := range finalBuffer {
-			finalBuffer[j] += int(volume * 32767 * noteBuffer[j] / float64(len(frequencies)))
+			finalBuffer[j] += sb.volume * noteBuffer[j] / float64(len(frequencies))
 		}
 	}
 
@@ -0,0 +1,97 @@
+package godio
+
+import (
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/go-audio/wav"
+)
+
+func TestNewSoundBufferOptions(t *testing.T) {
+	parameters := []struct {
+		name  string
+		opts  []Option
+		valid bool
+	}{
+		{"defaults", nil, true},
+		{"48 kHz 24-bit stereo", []Option{WithSampleRate(48000), WithSampleFormat(SampleFormatInt24), WithChannels(2)}, true},
+		{"192 kHz float", []Option{WithSampleRate(192000), WithSampleFormat(SampleFormatFloat32)}, true},
+		{"8 kHz", []Option{WithSampleRate(8000)}, false},
+		{"384 kHz", []Option{WithSampleRate(384000)}, false},
+		{"8-bit", []Option{WithSampleFormat("int8")}, false},
+		{"no channels", []Option{WithChannels(0)}, false},
+		{"loud", []Option{WithVolume(1.5)}, false},
+	}
+
+	for _, p := range parameters {
+		t.Run(fmt.Sprintf("Testing %v", p.name), func(t *testing.T) {
+			_, err := NewSoundBufferE(p.opts...)
+			if (err == nil) != p.valid {
+				t.Errorf("Expected valid %v, but got %v", p.valid, err)
+			}
+		})
+	}
+}
+
+func TestSoundBufferWrite(t *testing.T) {
+	parameters := []struct {
+		format      SampleFormat
+		bitDepth    int
+		audioFormat int
+	}{
+		{SampleFormatInt16, 16, wavFormatPCM},
+		{SampleFormatInt24, 24, wavFormatPCM},
+		{SampleFormatInt32, 32, wavFormatPCM},
+		{SampleFormatFloat32, 32, wavFormatIEEEFloat},
+	}
+
+	for _, p := range parameters {
+		t.Run(fmt.Sprintf("Testing %v", p.format), func(t *testing.T) {
+			sb := NewSoundBuffer(WithSampleRate(48000), WithSampleFormat(p.format), WithChannels(2))
+			sb.AppendChord([]float64{220, 330}, 0.5, WaveformSine)
+
+			file, err := os.Create(filepath.Join(t.TempDir(), "chord.wav"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer file.Close()
+			if err := sb.Write(file); err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			decoder := wav.NewDecoder(file)
+			decoder.ReadInfo()
+			if decoder.SampleRate != 48000 || decoder.NumChans != 2 || int(decoder.BitDepth) != p.bitDepth || int(decoder.WavAudioFormat) != p.audioFormat {
+				t.Errorf("Expected 48000 Hz, 2 channels, %v bits and format %v, but got %v Hz, %v channels, %v bits and format %v",
+					p.bitDepth, p.audioFormat, decoder.SampleRate, decoder.NumChans, decoder.BitDepth, decoder.WavAudioFormat)
+			}
+			if duration, err := decoder.Duration(); err != nil || math.Abs(duration.Seconds()-0.5) > 0.001 {
+				t.Errorf("Expected 0.5 seconds, but got %v (%v)", duration, err)
+			}
+		})
+	}
+}
+
+func TestApplyADSRSampleRate(t *testing.T) {
+	for _, rate := range []int{22050, 44100, 96000} {
+		t.Run(fmt.Sprintf("Testing %v Hz", rate), func(t *testing.T) {
+			sb := NewSoundBuffer(WithSampleRate(rate), WithVolume(1))
+			sb.AppendNote(100, 1, WaveformSquare)
+			sb.ApplyADSR(ADSREnvelope{Attack: 100, Decay: 0, Sustain: 1, Release: 100})
+
+			// Halfway through the attack and the release, the square wave is at half amplitude
+			samples := sb.combineBuffers()
+			for _, i := range []int{rate / 20, rate - rate/20} {
+				if amplitude := math.Abs(samples[i]); math.Abs(amplitude-0.5) > 0.01 {
+					t.Errorf("Expected an amplitude of 0.5 at sample %v, but got %v", i, amplitude)
+				}
+			}
+			if sb.Len() != rate {
+				t.Errorf("Expected %v samples, but got %v", rate, sb.Len())
+			}
+		})
+	}
+}
@@ -0,0 +1,153 @@
+package guitar
+
+import (
+	"cmp"
+	"slices"
+
+	"github.com/kimond/godio/pkg/godio"
+	"github.com/samber/lo"
+)
+
+// Fretboard finds the playable fingerings of chords on a guitar
+type Fretboard struct {
+	Tuning     Tuning
+	Frets      int // Highest fret a fingering may use
+	MaxSpan    int // Largest number of frets covered by the fretted strings
+	MinStrings int // Fewest strings a fingering plays
+}
+
+// NewFretboard returns a Fretboard for a tuning using the first 12 frets, with
+// fingerings covering at most 4 frets and playing at least 4 strings
+func NewFretboard(tuning Tuning) *Fretboard {
+	return &Fretboard{
+		Tuning:     tuning,
+		Frets:      12,
+		MaxSpan:    4,
+		MinStrings: 4,
+	}
+}
+
+// Fingerings returns the playable fingerings of a chord, the easiest first. A
+// fingering plays only chord tones with the bass note on its lowest string, and
+// plays every tone left by the voicing rules of the chord except the perfect
+// fifth. When no fingering plays them all, the tensions are left out one at a
+// time from the lowest above the root, so that chords such as C7alt and C13 are
+// played with their guide tones and highest tensions. It is fretted with at
+// most four fingers, counting a barre as one.
+func (fb *Fretboard) Fingerings(c *godio.Chord) []Fingering {
+	allowed := c.PitchClasses()
+	required := c.VoicedPitchClasses()
+	bass := int(c.BassNote.PitchClass())
+	if fifth := int(c.Root.PitchClass().Add(godio.PerfectFifth)); fifth != bass {
+		required = lo.Without(required, fifth)
+	}
+
+	tensions := lo.Without(tensionsOf(c, required), bass)
+	for {
+		fingerings := fb.fingerings(allowed, bass, required)
+		if len(fingerings) > 0 || len(tensions) == 0 {
+			return fingerings
+		}
+		required = lo.Without(required, tensions[0])
+		tensions = tensions[1:]
+	}
+}
+
+// fingerings returns the playable fingerings playing allowed pitch classes with
+// the bass and every required pitch class, the easiest first
+func (fb *Fretboard) fingerings(allowed []int, bass int, required []int) []Fingering {
+	seen := map[string]bool{}
+	fingerings := []Fingering{}
+	for position := 1; position+fb.MaxSpan-1 <= max(fb.Frets, fb.MaxSpan); position++ {
+		for _, fingering := range fb.candidates(allowed, position) {
+			if seen[fingering.String()] || !fb.playable(fingering, bass, required) {
+				continue
+			}
+			seen[fingering.String()] = true
+			fingerings = append(fingerings, fingering)
+		}
+	}
+
+	slices.SortStableFunc(fingerings, func(a, b Fingering) int {
+		return cmp.Compare(difficulty(a), difficulty(b))
+	})
+	return fingerings
+}
+
+// tensionsOf returns the pitch classes among classes that are neither the root,
+// the third nor the seventh of the chord, from the lowest above the root. The
+// suspended second or fourth stands for a missing third, and the sixth for a
+// missing seventh.
+func tensionsOf(c *godio.Chord, classes []int) []int {
+	root := int(c.Root.PitchClass())
+	intervals := lo.Map(classes, func(class int, _ int) int { return mod12(class - root) })
+	guides := []int{0, 3, 4, 10, 11}
+	if !slices.Contains(intervals, 3) && !slices.Contains(intervals, 4) {
+		guides = append(guides, 2, 5)
+	}
+	if !slices.Contains(intervals, 10) && !slices.Contains(intervals, 11) {
+		guides = append(guides, 9)
+	}
+	tensions := lo.Filter(intervals, func(interval int, _ int) bool { return !slices.Contains(guides, interval) })
+	slices.Sort(tensions)
+	return lo.Map(tensions, func(interval int, _ int) int { return mod12(root + interval) })
+}
+
+// candidates returns the fingerings playing chord tones, open strings or frets
+// from position to position+MaxSpan-1 on each string
+func (fb *Fretboard) candidates(allowed []int, position int) []Fingering {
+	all := []Fingering{{}}
+	for _, open := range fb.Tuning {
+		frets := []int{Muted}
+		for _, fret := range append([]int{0}, lo.RangeFrom(position, fb.MaxSpan)...) {
+			if fret <= fb.Frets && slices.Contains(allowed, int(open.Note.PitchClass().Add(godio.Semitones(fret)))) {
+				frets = append(frets, fret)
+			}
+		}
+
+		extended := make([]Fingering, 0, len(all)*len(frets))
+		for _, fingering := range all {
+			for _, fret := range frets {
+				extended = append(extended, append(slices.Clone(fingering), fret))
+			}
+		}
+		all = extended
+	}
+	return all
+}
+
+// playable reports whether the fingering has the bass note on its lowest string,
+// every required pitch class, enough strings and no more than four fingers
+func (fb *Fretboard) playable(f Fingering, bass int, required []int) bool {
+	notes, err := f.MIDI(fb.Tuning)
+	if err != nil || len(notes) == 0 || len(notes) < min(fb.MinStrings, len(fb.Tuning)) || mod12(notes[0]) != bass {
+		return false
+	}
+	classes := lo.Map(notes, func(note int, _ int) int { return mod12(note) })
+	return lo.Every(classes, required) && f.Span() <= fb.MaxSpan && f.Fingers() <= 4
+}
+
+// difficulty scores how hard a fingering is to play: wide stretches, high
+// positions, muted strings between played ones and barres are harder, while
+// open strings are easier.
+func difficulty(f Fingering) float64 {
+	played := lo.Filter(f, func(fret int, _ int) bool { return fret != Muted })
+	first, last := slices.IndexFunc(f, func(fret int) bool { return fret != Muted }), len(f)-1
+	for f[last] == Muted {
+		last--
+	}
+	innerMutes := lo.Count(f[first:last+1], Muted)
+
+	score := float64(f.Span()) + 0.5*float64(f.Fingers()) + 0.5*float64(f.Position())
+	score += 2*float64(innerMutes) + float64(len(f)-len(played))
+	score -= 0.5 * float64(lo.Count(f, 0))
+	if f.Barre() != nil {
+		score += 1.5
+	}
+	return score
+}
+
+// mod12 returns n modulo 12 from 0 to 11, even for negative numbers
+func mod12(n int) int {
+	return ((n % 12) + 12) % 12
+}
@@ -0,0 +1,320 @@
+// Package guitar finds and renders guitar fingerings of godio chords.
+package guitar
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/kimond/godio/pkg/godio"
+	"github.com/samber/lo"
+)
+
+// Tuning is the pitches of the open strings of a guitar, from the lowest string to the highest
+type Tuning []godio.Pitch
+
+var (
+	StandardTuning = parseTuning("E2 A2 D3 G3 B3 E4")
+	DropDTuning    = parseTuning("D2 A2 D3 G3 B3 E4")
+	DADGADTuning   = parseTuning("D2 A2 D3 G3 A3 D4")
+)
+
+// Tunings are the guitar tunings accepted by ParseTuning
+var Tunings = map[string]Tuning{
+	"standard": StandardTuning,
+	"dropd":    DropDTuning,
+	"dadgad":   DADGADTuning,
+}
+
+// ParseTuning parses the name of one of Tunings, or the pitches of the open
+// strings separated by spaces or commas, lowest first, such as "C2,G2,D3,G3,B3,E4"
+func ParseTuning(s string) (Tuning, error) {
+	if tuning, ok := Tunings[strings.ToLower(s)]; ok {
+		return tuning, nil
+	}
+	fields := strings.FieldsFunc(s, func(r rune) bool { return r == ',' || r == ' ' })
+	if len(fields) < 2 {
+		names := lo.Keys(Tunings)
+		slices.Sort(names)
+		return nil, fmt.Errorf("unknown guitar tuning %q, expected one of %s or the pitches of the open strings", s, strings.Join(names, ", "))
+	}
+	tuning := Tuning{}
+	for _, field := range fields {
+		pitch, err := godio.ParsePitchE(field)
+		if err != nil {
+			return nil, fmt.Errorf("invalid guitar tuning %q: %w", s, err)
+		}
+		tuning = append(tuning, pitch)
+	}
+	return tuning, nil
+}
+
+// parseTuning parses open string pitches that are known to be valid
+func parseTuning(s string) Tuning {
+	return lo.Map(strings.Fields(s), func(pitch string, _ int) godio.Pitch {
+		return godio.ParsePitch(pitch)
+	})
+}
+
+func (t Tuning) String() string {
+	return strings.Join(lo.Map(t, func(pitch godio.Pitch, _ int) string { return pitch.String() }), " ")
+}
+
+// Muted is the fret of a string that is not played
+const Muted = -1
+
+// Fingering is the fret played on each string, from the lowest string to the
+// highest, 0 for an open string and Muted for a string that is not played
+type Fingering []int
+
+// ParseFingering parses a fingering written as one character per string, such as
+// "x32010", or with frets separated by dashes when some are above 9, as in "x-10-12-11-12-x"
+func ParseFingering(s string) (Fingering, error) {
+	if s == "" {
+		return nil, fmt.Errorf("invalid fingering %q: expected a fret or x for each string", s)
+	}
+	fields := strings.Split(s, "-")
+	if len(fields) == 1 {
+		fields = strings.Split(s, "")
+	}
+	fingering := Fingering{}
+	for _, field := range fields {
+		if strings.EqualFold(field, "x") {
+			fingering = append(fingering, Muted)
+			continue
+		}
+		fret, err := strconv.Atoi(field)
+		if err != nil || fret < 0 {
+			return nil, fmt.Errorf("invalid fingering %q: expected a fret or x, got %q", s, field)
+		}
+		fingering = append(fingering, fret)
+	}
+	return fingering, nil
+}
+
+func (f Fingering) String() string {
+	frets := lo.Map(f, func(fret int, _ int) string {
+		if fret == Muted {
+			return "x"
+		}
+		return strconv.Itoa(fret)
+	})
+	if slices.ContainsFunc(f, func(fret int) bool { return fret > 9 }) {
+		return strings.Join(frets, "-")
+	}
+	return strings.Join(frets, "")
+}
+
+// MIDI returns the MIDI note numbers of the played strings, from the lowest string
+// to the highest. An error is returned when the fingering and the tuning do not
+// have the same number of strings.
+func (f Fingering) MIDI(tuning Tuning) ([]int, error) {
+	if len(f) != len(tuning) {
+		return nil, fmt.Errorf("fingering %v has %d strings, but the tuning %v has %d", f, len(f), tuning, len(tuning))
+	}
+	notes := []int{}
+	for i, fret := range f {
+		if fret != Muted {
+			notes = append(notes, tuning[i].MIDI()+fret)
+		}
+	}
+	return notes, nil
+}
+
+// Frequencies returns the frequencies of the played strings in a tuning, in strum
+// order. An error is returned when the fingering does not fit the guitar tuning.
+func (f Fingering) Frequencies(guitarTuning Tuning, tuning godio.Tuning) ([]float64, error) {
+	notes, err := f.MIDI(guitarTuning)
+	if err != nil {
+		return nil, err
+	}
+	return godio.Voicing(notes).Frequencies(tuning), nil
+}
+
+// Span returns the number of frets covered by the fretted strings, 0 when all strings are open or muted
+func (f Fingering) Span() int {
+	fretted := f.fretted()
+	if len(fretted) == 0 {
+		return 0
+	}
+	return slices.Max(fretted) - slices.Min(fretted) + 1
+}
+
+// Position returns the lowest fretted fret, 0 when all strings are open or muted
+func (f Fingering) Position() int {
+	fretted := f.fretted()
+	if len(fretted) == 0 {
+		return 0
+	}
+	return slices.Min(fretted)
+}
+
+// fretted returns the frets of the strings that are neither open nor muted
+func (f Fingering) fretted() []int {
+	return lo.Filter(f, func(fret int, _ int) bool { return fret > 0 })
+}
+
+// Barre is a finger pressing several strings on the same fret
+type Barre struct {
+	Fret int
+	From int // Index of the lowest string of the barre
+	To   int // Index of the highest string of the barre
+}
+
+// Barre returns the barre of the fingering, or nil when there is none. A barre
+// is needed when more than four strings are fretted, and lies on the lowest
+// fretted fret when it is played on several strings and every string between
+// them is fretted.
+func (f Fingering) Barre() *Barre {
+	fret := f.Position()
+	if len(f.fretted()) <= 4 {
+		return nil
+	}
+	from, to := slices.Index(f, fret), len(f)-1-slices.Index(lo.Reverse(slices.Clone(f)), fret)
+	if from == to {
+		return nil
+	}
+	for _, covered := range f[from : to+1] {
+		if covered < fret {
+			return nil
+		}
+	}
+	return &Barre{Fret: fret, From: from, To: to}
+}
+
+// Fingers returns the number of fingers needed to fret the fingering, counting a barre as one finger
+func (f Fingering) Fingers() int {
+	fingers := len(f.fretted())
+	if barre := f.Barre(); barre != nil {
+		fingers -= lo.Count(f[barre.From:barre.To+1], barre.Fret) - 1
+	}
+	return fingers
+}
+
+// Diagram draws the fingering as an ASCII chord diagram with the lowest string
+// on the left. Open and muted strings are marked with o and x above the nut,
+// fretted strings with O and barres with a line of O. Diagrams away from the
+// nut show the position of their first fret. Fingerings without strings have
+// an empty diagram.
+func (f Fingering) Diagram() string {
+	if len(f) == 0 {
+		return ""
+	}
+	first, rows := f.window()
+	barre := f.Barre()
+
+	var b strings.Builder
+	b.WriteString(strings.Join(lo.Map(f, func(fret int, _ int) string {
+		switch fret {
+		case Muted:
+			return "x"
+		case 0:
+			return "o"
+		}
+		return " "
+	}), " "))
+	b.WriteString("\n")
+	if first == 1 {
+		b.WriteString(strings.Repeat("=", 2*len(f)-1) + "\n")
+	} else {
+		b.WriteString(strings.Repeat("-", 2*len(f)-1) + "\n")
+	}
+
+	for fret := first; fret < first+rows; fret++ {
+		for i, played := range f {
+			switch {
+			case played == fret:
+				b.WriteString("O")
+			case barre != nil && barre.Fret == fret && i >= barre.From && i <= barre.To:
+				b.WriteString("O")
+			default:
+				b.WriteString("|")
+			}
+			if i < len(f)-1 {
+				if barre != nil && barre.Fret == fret && i >= barre.From && i < barre.To {
+					b.WriteString("-")
+				} else {
+					b.WriteString(" ")
+				}
+			}
+		}
+		if fret == first && first > 1 {
+			fmt.Fprintf(&b, " %dfr", first)
+		}
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+// SVG draws the fingering as an SVG chord diagram laid out like Diagram
+func (f Fingering) SVG() string {
+	const (
+		spacing, fretHeight = 20, 24
+		left, top           = 30, 30
+	)
+	first, rows := f.window()
+	barre := f.Barre()
+	right, bottom := left+spacing*(len(f)-1), top+fretHeight*rows
+	x := func(str int) int { return left + spacing*str }
+	y := func(fret int) int { return top + fretHeight*(fret-first) + fretHeight/2 }
+
+	var b strings.Builder
+	fmt.Fprintf(&b, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d">`+"\n", right+left, bottom+10, right+left, bottom+10)
+	for fret := 0; fret <= rows; fret++ {
+		width := 1
+		if fret == 0 && first == 1 {
+			width = 4
+		}
+		fmt.Fprintf(&b, `  <line x1="%d" y1="%d" x2="%d" y2="%d" stroke="black" stroke-width="%d"/>`+"\n", left, top+fretHeight*fret, right, top+fretHeight*fret, width)
+	}
+	for str := range f {
+		fmt.Fprintf(&b, `  <line x1="%d" y1="%d" x2="%d" y2="%d" stroke="black"/>`+"\n", x(str), top, x(str), bottom)
+	}
+	if first > 1 {
+		fmt.Fprintf(&b, `  <text x="%d" y="%d" font-family="sans-serif" font-size="12" text-anchor="end">%dfr</text>`+"\n", left-8, y(first)+4, first)
+	}
+	if barre != nil {
+		fmt.Fprintf(&b, `  <rect x="%d" y="%d" width="%d" height="%d" rx="7" fill="black"/>`+"\n", x(barre.From)-7, y(barre.Fret)-7, x(barre.To)-x(barre.From)+14, 14)
+	}
+	for str, fret := range f {
+		switch fret {
+		case Muted:
+			fmt.Fprintf(&b, `  <text x="%d" y="%d" font-family="sans-serif" font-size="12" text-anchor="middle">x</text>`+"\n", x(str), top-8)
+		case 0:
+			fmt.Fprintf(&b, `  <circle cx="%d" cy="%d" r="5" fill="none" stroke="black"/>`+"\n", x(str), top-12)
+		default:
+			fmt.Fprintf(&b, `  <circle cx="%d" cy="%d" r="7" fill="black"/>`+"\n", x(str), y(fret))
+		}
+	}
+	b.WriteString("</svg>\n")
+	return b.String()
+}
+
+// window returns the first fret shown in diagrams of the fingering, 1 unless it
+// lies above the fourth fret, and the number of frets shown
+func (f Fingering) window() (int, int) {
+	first := 1
+	if f.Position()+f.Span()-1 > 4 {
+		first = f.Position()
+	}
+	return first, max(4, f.Position()+f.Span()-first)
+}
+
+// DefaultStrum and DefaultEnvelope render fingerings as a quick downstroke that rings out
+var (
+	DefaultStrum    = godio.StrumParams{Duration: 40, Randomness: 0.2}
+	DefaultEnvelope = godio.ADSREnvelope{Attack: 5, Decay: 600, Sustain: 0.4, Release: 150}
+)
+
+// Render appends the fingering strummed from the lowest string to the highest
+// with DefaultStrum and DefaultEnvelope. An error is returned when the fingering
+// does not fit the guitar tuning.
+func Render(sb *godio.SoundBuffer, fingering Fingering, guitarTuning Tuning, tuning godio.Tuning, durationSec float64, waveform godio.Waveform) error {
+	frequencies, err := fingering.Frequencies(guitarTuning, tuning)
+	if err != nil {
+		return err
+	}
+	sb.AppendChordWithStrum(frequencies, durationSec, waveform, DefaultStrum, DefaultEnvelope)
+	return nil
+}
@@ -0,0 +1,214 @@
+package guitar
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/kimond/godio/pkg/godio"
+	"github.com/samber/lo"
+)
+
+func TestParseTuning(t *testing.T) {
+	parameters := []struct {
+		input    string
+		expected string
+	}{
+		{"standard", "E2 A2 D3 G3 B3 E4"},
+		{"DropD", "D2 A2 D3 G3 B3 E4"},
+		{"dadgad", "D2 A2 D3 G3 A3 D4"},
+		{"C2,G2,D3,G3,B3,E4", "C2 G2 D3 G3 B3 E4"},
+		{"E1 A1 D2 G2", "E1 A1 D2 G2"},
+	}
+
+	for _, p := range parameters {
+		t.Run(fmt.Sprintf("Testing %v", p.input), func(t *testing.T) {
+			tuning, err := ParseTuning(p.input)
+			if err != nil {
+				t.Fatalf("Expected %v, but got %v", p.expected, err)
+			}
+			if tuning.String() != p.expected {
+				t.Errorf("Expected %v, but got %v", p.expected, tuning)
+			}
+		})
+	}
+
+	for _, input := range []string{"banjo", "E2,H2"} {
+		if _, err := ParseTuning(input); err == nil {
+			t.Errorf("Expected an error for %v, but got nil", input)
+		}
+	}
+}
+
+func TestFingering(t *testing.T) {
+	parameters := []struct {
+		input   string
+		span    int
+		fingers int
+		barre   *Barre
+	}{
+		{"x32010", 3, 3, nil},
+		{"133211", 3, 4, &Barre{Fret: 1, From: 0, To: 5}},
+		{"x35553", 3, 4, &Barre{Fret: 3, From: 1, To: 5}},
+		{"000232", 2, 3, nil},
+		{"x-x-10-12-12-12", 3, 4, nil},
+		{"xx0000", 0, 0, nil},
+	}
+
+	for _, p := range parameters {
+		t.Run(fmt.Sprintf("Testing %v", p.input), func(t *testing.T) {
+			fingering, err := ParseFingering(p.input)
+			if err != nil {
+				t.Fatalf("Expected a fingering, but got %v", err)
+			}
+			if fingering.String() != p.input {
+				t.Errorf("Expected %v, but got %v", p.input, fingering)
+			}
+			if fingering.Span() != p.span {
+				t.Errorf("Expected a span of %v, but got %v", p.span, fingering.Span())
+			}
+			if fingering.Fingers() != p.fingers {
+				t.Errorf("Expected %v fingers, but got %v", p.fingers, fingering.Fingers())
+			}
+			if barre := fingering.Barre(); (barre == nil) != (p.barre == nil) || (barre != nil && *barre != *p.barre) {
+				t.Errorf("Expected the barre %v, but got %v", p.barre, barre)
+			}
+		})
+	}
+
+	for _, input := range []string{"", "x3a010", "x--2"} {
+		if _, err := ParseFingering(input); err == nil {
+			t.Errorf("Expected an error for %q, but got nil", input)
+		}
+	}
+	if diagram := (Fingering{}).Diagram(); diagram != "" {
+		t.Errorf("Expected an empty diagram, but got %v", diagram)
+	}
+}
+
+func TestDiagram(t *testing.T) {
+	parameters := []struct {
+		fingering string
+		expected  string
+	}{
+		{"x32010", "" +
+			"x     o   o\n" +
+			"===========\n" +
+			"| | | | O |\n" +
+			"| | O | | |\n" +
+			"| O | | | |\n" +
+			"| | | | | |\n"},
+		{"x35553", "" +
+			"x          \n" +
+			"-----------\n" +
+			"| O-O-O-O-O 3fr\n" +
+			"| | | | | |\n" +
+			"| | O O O |\n" +
+			"| | | | | |\n"},
+	}
+
+	for _, p := range parameters {
+		t.Run(fmt.Sprintf("Testing %v", p.fingering), func(t *testing.T) {
+			fingering, _ := ParseFingering(p.fingering)
+			if diagram := fingering.Diagram(); diagram != p.expected {
+				t.Errorf("Expected\n%v, but got\n%v", p.expected, diagram)
+			}
+		})
+	}
+}
+
+func TestFingerings(t *testing.T) {
+	parameters := []struct {
+		chord    string
+		tuning   Tuning
+		expected string
+	}{
+		{"C", StandardTuning, "x32010"},
+		{"Cmaj7", StandardTuning, "x32000"},
+		{"G", StandardTuning, "320003"},
+		{"Am7", StandardTuning, "x02010"},
+		{"E7", StandardTuning, "020100"},
+		{"D", StandardTuning, "xx0232"},
+		{"D", DropDTuning, "000232"},
+		{"Dsus4", DADGADTuning, "000000"},
+		{"C13", StandardTuning, "800056"},
+		{"C7alt", StandardTuning, "898890"},
+		{"C7b5", StandardTuning, "x34310"},
+		{"C7#5", StandardTuning, "x36350"},
+		{"Bm7b5", StandardTuning, "x20201"},
+	}
+
+	for _, p := range parameters {
+		t.Run(fmt.Sprintf("Testing %v in %v", p.chord, p.tuning), func(t *testing.T) {
+			chord := godio.MustParseChord(p.chord)
+			fretboard := NewFretboard(p.tuning)
+			fingerings := fretboard.Fingerings(chord)
+			if len(fingerings) == 0 || fingerings[0].String() != p.expected {
+				t.Fatalf("Expected %v first, but got %v", p.expected, fingerings[:min(3, len(fingerings))])
+			}
+
+			for _, fingering := range fingerings {
+				notes, err := fingering.MIDI(p.tuning)
+				if err != nil {
+					t.Fatalf("Unexpected error: %v", err)
+				}
+				classes := lo.Map(notes, func(note int, _ int) int { return mod12(note) })
+				if !lo.Every(chord.PitchClasses(), classes) || classes[0] != int(chord.BassNote.PitchClass()) {
+					t.Errorf("Expected chord tones with the bass first, but got %v", fingering)
+				}
+				if fingering.Span() > fretboard.MaxSpan || fingering.Fingers() > 4 || slices.Max(fingering) > fretboard.Frets {
+					t.Errorf("Expected a playable fingering, but got %v", fingering)
+				}
+			}
+		})
+	}
+}
+
+func TestFrequencies(t *testing.T) {
+	fingering, _ := ParseFingering("x32010")
+	frequencies, err := fingering.Frequencies(StandardTuning, godio.StandardTuning)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	expected := lo.Map([]string{"C3", "E3", "G3", "C4", "E4"}, func(pitch string, _ int) float64 {
+		return godio.ParsePitch(pitch).Frequency()
+	})
+	if !slices.Equal(frequencies, expected) {
+		t.Errorf("Expected %v, but got %v", expected, frequencies)
+	}
+
+	seven, _ := ParseFingering("x320100")
+	if _, err := seven.Frequencies(StandardTuning, godio.StandardTuning); err == nil {
+		t.Errorf("Expected an error for a fingering with more strings than the tuning")
+	}
+}
+
+func TestSVG(t *testing.T) {
+	parameters := []struct {
+		fingering string
+		dots      int
+		open      int
+		barre     bool
+	}{
+		{"x32010", 3, 2, false},
+		{"133211", 6, 0, true},
+		{"x-x-10-12-12-12", 4, 0, false},
+	}
+
+	for _, p := range parameters {
+		t.Run(fmt.Sprintf("Testing %v", p.fingering), func(t *testing.T) {
+			fingering, _ := ParseFingering(p.fingering)
+			svg := fingering.SVG()
+			if dots := strings.Count(svg, `r="7"`); dots != p.dots {
+				t.Errorf("Expected %v fretted strings, but got %v", p.dots, dots)
+			}
+			if open := strings.Count(svg, `r="5"`); open != p.open {
+				t.Errorf("Expected %v open strings, but got %v", p.open, open)
+			}
+			if barre := strings.Contains(svg, "<rect"); barre != p.barre {
+				t.Errorf("Expected a barre %v, but got %v", p.barre, barre)
+			}
+		})
+	}
+}
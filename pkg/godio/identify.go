@@ -0,0 +1,180 @@
+package godio
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+
+	"github.com/samber/lo"
+)
+
+// ChordCandidate is a possible name for a set of pitches as returned by IdentifyChord
+type ChordCandidate struct {
+	Chord     *Chord
+	Score     float64 // Higher scores are better matches
+	Inversion int     // 0 in root position, 1 to 3 with the third, fifth or seventh in the bass, -1 for any other bass note
+}
+
+func (c ChordCandidate) String() string {
+	return fmt.Sprintf("%s (%.1f)", c.Chord, c.Score)
+}
+
+// Scoring weights used to rank chord candidates
+const (
+	identifyBaseScore        = 3.0
+	identifyMissingPenalty   = 1.5  // For each missing formula tone other than the fifth
+	identifyMissingFifth     = 0.25 // The fifth is often left out of voicings
+	identifyExtensionCost    = 0.6  // For each tone named as an extension
+	identifyRootBassBonus    = 0.5
+	identifySlashBassPenalty = 0.3 // When the bass is not the root, third, fifth or seventh
+)
+
+// identifySpelling names the roots and bass notes of identified chords as they
+// are usually written on charts, with flats except for F#
+var identifySpelling = notesSpelling(append(append(slices.Clone(flatNotes[:6]), sharpNotes[6]), flatNotes[7:]...))
+
+// identifyQualities returns the qualities of DefaultChordRegistry tried by IdentifyChord.
+// Qualities sharing a formula with a shorter one, such as "maj" and "minmaj7", are skipped.
+func identifyQualities() []string {
+	keys := DefaultChordRegistry.Qualities()
+	sort.Slice(keys, func(i, j int) bool {
+		if len(keys[i]) != len(keys[j]) {
+			return len(keys[i]) < len(keys[j])
+		}
+		return keys[i] < keys[j]
+	})
+	return lo.UniqBy(keys, func(key string) string {
+		return fmt.Sprint(DefaultChordRegistry.formula(key))
+	})
+}
+
+// IdentifyChord ranks the chord names matching a set of MIDI note numbers, best match first.
+// The lowest note is the bass, so inversions and slash chords are recognized.
+// Candidates are built from the qualities of DefaultChordRegistry, with remaining tones named as extensions.
+// At least two distinct pitch classes are needed to name a chord.
+func IdentifyChord(pitches []int) []ChordCandidate {
+	classes := lo.Uniq(lo.Map(pitches, func(pitch int, _ int) int {
+		return mod12(pitch)
+	}))
+	if len(classes) < 2 {
+		return nil
+	}
+	bass := mod12(slices.Min(pitches))
+
+	qualities := identifyQualities()
+	best := map[string]ChordCandidate{}
+	for _, root := range classes {
+		for _, quality := range qualities {
+			for _, sus := range []string{"", "sus2", "sus4"} {
+				candidate, ok := matchChord(classes, root, bass, quality, sus)
+				if !ok {
+					continue
+				}
+				// Different spellings of the same notes keep the best scoring one
+				key := fmt.Sprint(candidate.Chord.Root, candidate.Chord.PitchClasses())
+				if previous, found := best[key]; !found || candidate.Score > previous.Score {
+					best[key] = candidate
+				}
+			}
+		}
+	}
+
+	candidates := lo.Values(best)
+	sort.Slice(candidates, func(i, j int) bool {
+		if candidates[i].Score != candidates[j].Score {
+			return candidates[i].Score > candidates[j].Score
+		}
+		return candidates[i].Chord.String() < candidates[j].Chord.String()
+	})
+	return candidates
+}
+
+// IdentifyChordFrequencies is like IdentifyChord for frequencies in Hz, matched to the closest notes in StandardTuning
+func IdentifyChordFrequencies(frequencies []float64) []ChordCandidate {
+	return IdentifyChord(lo.Map(frequencies, func(frequency float64, _ int) int {
+		return StandardTuning.Note(frequency)
+	}))
+}
+
+// matchChord scores the chord with the given root, quality and suspension against
+// the pitch classes. It fails when a pitch class cannot be named as an extension.
+func matchChord(classes []int, root int, bass int, quality string, sus string) (ChordCandidate, bool) {
+	core := lo.Uniq(lo.Map(DefaultChordRegistry.formula(quality), func(tone Interval, _ int) int {
+		return mod12(tone.Semitones)
+	}))
+	if sus != "" {
+		// Only chords with a major third and a perfect fifth are suspended
+		if !slices.Contains(core, 4) || !slices.Contains(core, 7) {
+			return ChordCandidate{}, false
+		}
+		core = append(lo.Without(core, 4), mod12(extensionFormulas[sus].Semitones))
+	}
+
+	present := lo.Without(lo.Map(classes, func(class int, _ int) int {
+		return mod12(class - root)
+	}), 0)
+	slices.Sort(present)
+
+	score := identifyBaseScore
+	for _, tone := range core {
+		if slices.Contains(present, tone) {
+			continue
+		}
+		if tone == 7 {
+			score -= identifyMissingFifth
+		} else {
+			score -= identifyMissingPenalty
+		}
+	}
+
+	symbol := &ChordSymbol{Root: identifySpelling.Spell(PitchClass(root)).String(), Quality: quality}
+	if sus != "" {
+		symbol.Alterations = append(symbol.Alterations, sus)
+	}
+	hasFifth := slices.Contains(present, 7)
+	for _, tone := range present {
+		if slices.Contains(core, tone) {
+			continue
+		}
+		extension, ok := tensionNames[tone]
+		// Altered fifths are named separately since they depend on the presence of the natural fifth
+		switch {
+		case tone == 6 && !hasFifth:
+			extension, ok = "b5", true
+		case tone == 8 && !hasFifth:
+			extension, ok = "#5", true
+		}
+		if !ok {
+			return ChordCandidate{}, false
+		}
+		if extension[0] >= '0' && extension[0] <= '9' {
+			symbol.Additions = append(symbol.Additions, extension)
+		} else {
+			symbol.Alterations = append(symbol.Alterations, extension)
+		}
+		score -= identifyExtensionCost
+	}
+
+	inversion := -1
+	switch bassTone := mod12(bass - root); {
+	case bassTone == 0:
+		inversion = 0
+		score += identifyRootBassBonus
+	case slices.Contains(core, bassTone) && (bassTone == 3 || bassTone == 4):
+		inversion = 1
+	case slices.Contains(core, bassTone) && bassTone >= 6 && bassTone <= 8:
+		inversion = 2
+	case slices.Contains(core, bassTone) && (bassTone >= 10 || bassTone == 9 && quality == "dim7"):
+		inversion = 3
+	default:
+		score -= identifySlashBassPenalty
+	}
+	if inversion != 0 {
+		symbol.Bass = identifySpelling.Spell(PitchClass(bass)).String()
+	}
+
+	if score <= 0 {
+		return ChordCandidate{}, false
+	}
+	return ChordCandidate{Chord: symbol.Chord(), Score: score, Inversion: inversion}, true
+}
@@ -0,0 +1,62 @@
+package godio
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/samber/lo"
+)
+
+func TestIdentifyChord(t *testing.T) {
+	parameters := []struct {
+		pitches   []int
+		expected  string
+		inversion int
+	}{
+		{[]int{52, 55, 60}, "C/E", 1},
+		{[]int{60, 64, 67, 70}, "C7", 0},
+		{[]int{57, 60, 64, 67}, "Am7", 0},
+		{[]int{60, 65, 67}, "Csus4", 0},
+		{[]int{62, 65, 69, 72, 76}, "Dm9", 0},
+		{[]int{55, 59, 62, 65, 68}, "G7b9", 0},
+		{[]int{43, 62, 65, 71}, "G7", 0},
+		{[]int{60, 64, 68, 71}, "Caugmaj7", 0},
+		{[]int{63, 67, 70}, "Eb", 0},
+		{[]int{58, 62, 65, 68}, "Bb7", 0},
+		{[]int{54, 58, 61}, "F#", 0},
+		{[]int{56, 60, 63, 66}, "Ab7", 0},
+		{[]int{51, 56, 60, 66}, "Ab7/Eb", 2},
+	}
+
+	for _, p := range parameters {
+		t.Run(fmt.Sprintf("Testing %v", p.pitches), func(t *testing.T) {
+			candidates := IdentifyChord(p.pitches)
+			if len(candidates) == 0 {
+				t.Fatalf("Expected %s, but got no candidates", p.expected)
+			}
+			names := lo.Map(candidates, func(candidate ChordCandidate, _ int) string { return candidate.Chord.String() })
+			if len(lo.Uniq(names)) != len(names) {
+				t.Errorf("Expected each name once, but got %v", names)
+			}
+			best := candidates[0]
+			if best.Chord.String() != p.expected || best.Inversion != p.inversion {
+				t.Errorf("Expected %s with inversion %d, but got %s with inversion %d", p.expected, p.inversion, best.Chord, best.Inversion)
+			}
+		})
+	}
+}
+
+func TestIdentifyChordSingleNote(t *testing.T) {
+	for _, pitches := range [][]int{nil, {60}, {48, 60, 72}} {
+		if candidates := IdentifyChord(pitches); len(candidates) != 0 {
+			t.Errorf("Expected no chord for %v, but got %v", pitches, candidates)
+		}
+	}
+}
+
+func TestIdentifyChordFrequencies(t *testing.T) {
+	candidates := IdentifyChordFrequencies([]float64{NoteFrequencies["E3"], NoteFrequencies["G3"], NoteFrequencies["C4"]})
+	if len(candidates) == 0 || candidates[0].Chord.String() != "C/E" {
+		t.Errorf("Expected C/E, but got %v", candidates)
+	}
+}
@@ -1,46 +1,303 @@
 package godio
 
-type Interval int
-
-const (
-	PerfectUnison    Interval = 0
-	MinorSecond      Interval = 1
-	MajorSecond      Interval = 2
-	MinorThird       Interval = 3
-	MajorThird       Interval = 4
-	PerfectFourth    Interval = 5
-	DiminishedFifth  Interval = 6
-	PerfectFifth     Interval = 7
-	MinorSixth       Interval = 8
-	MajorSixth       Interval = 9
-	MinorSeventh     Interval = 10
-	MajorSeventh     Interval = 11
-	PerfectOctave    Interval = 12
-	MinorNinth       Interval = 13
-	MajorNinth       Interval = 14
-	MinorTenth       Interval = 15
-	MajorTenth       Interval = 16
-	PerfectEleventh  Interval = 17
-	AugmentedEleven  Interval = 18
-	MinorThirteenth  Interval = 20
-	MajorThirteenth  Interval = 21
-	MinorFourteenth  Interval = 22
-	MajorFourteenth  Interval = 23
-	PerfectFifteenth Interval = 24
+import (
+	"fmt"
+	"strconv"
+	"strings"
 )
 
+// Interval is the distance between two notes, counted both in letters and in
+// semitones so that an augmented fourth (C to F#) differs from a diminished
+// fifth (C to Gb). Descending intervals have negative steps and semitones.
+type Interval struct {
+	Steps     int // Number of letters moved, 0 for a unison, 2 for a third and 8 for a ninth
+	Semitones int
+}
+
+// The common intervals are variables as Go has no constant structs. They are
+// shared by the whole package and must not be reassigned.
+var (
+	PerfectUnison     = Interval{0, 0}
+	AugmentedUnison   = Interval{0, 1}
+	MinorSecond       = Interval{1, 1}
+	MajorSecond       = Interval{1, 2}
+	AugmentedSecond   = Interval{1, 3}
+	MinorThird        = Interval{2, 3}
+	MajorThird        = Interval{2, 4}
+	PerfectFourth     = Interval{3, 5}
+	AugmentedFourth   = Interval{3, 6}
+	DiminishedFifth   = Interval{4, 6}
+	PerfectFifth      = Interval{4, 7}
+	AugmentedFifth    = Interval{4, 8}
+	MinorSixth        = Interval{5, 8}
+	MajorSixth        = Interval{5, 9}
+	DiminishedSeventh = Interval{6, 9}
+	MinorSeventh      = Interval{6, 10}
+	MajorSeventh      = Interval{6, 11}
+	PerfectOctave     = Interval{7, 12}
+	MinorNinth        = Interval{8, 13}
+	MajorNinth        = Interval{8, 14}
+	AugmentedNinth    = Interval{8, 15}
+	MinorTenth        = Interval{9, 15}
+	MajorTenth        = Interval{9, 16}
+	PerfectEleventh   = Interval{10, 17}
+	AugmentedEleventh = Interval{10, 18}
+	MinorThirteenth   = Interval{12, 20}
+	MajorThirteenth   = Interval{12, 21}
+	MinorFourteenth   = Interval{13, 22}
+	MajorFourteenth   = Interval{13, 23}
+	PerfectFifteenth  = Interval{14, 24}
+
+	// Deprecated: AugmentedEleven is AugmentedEleventh.
+	AugmentedEleven = AugmentedEleventh
+)
+
+// IntervalNames maps the short names of the common intervals up to two octaves to their Interval
 var IntervalNames = map[string]Interval{
-	"P1": PerfectUnison,
-	"m2": MinorSecond,
-	"M2": MajorSecond,
-	"m3": MinorThird,
-	"M3": MajorThird,
-	"P4": PerfectFourth,
-	"A4": DiminishedFifth,
-	"P5": PerfectFifth,
-	"m6": MinorSixth,
-	"M6": MajorSixth,
-	"m7": MinorSeventh,
-	"M7": MajorSeventh,
-	"P8": PerfectOctave,
+	"P1":  PerfectUnison,
+	"m2":  MinorSecond,
+	"M2":  MajorSecond,
+	"A2":  AugmentedSecond,
+	"m3":  MinorThird,
+	"M3":  MajorThird,
+	"P4":  PerfectFourth,
+	"A4":  AugmentedFourth,
+	"d5":  DiminishedFifth,
+	"P5":  PerfectFifth,
+	"A5":  AugmentedFifth,
+	"m6":  MinorSixth,
+	"M6":  MajorSixth,
+	"d7":  DiminishedSeventh,
+	"m7":  MinorSeventh,
+	"M7":  MajorSeventh,
+	"P8":  PerfectOctave,
+	"m9":  MinorNinth,
+	"M9":  MajorNinth,
+	"A9":  AugmentedNinth,
+	"m10": MinorTenth,
+	"M10": MajorTenth,
+	"P11": PerfectEleventh,
+	"A11": AugmentedEleventh,
+	"m13": MinorThirteenth,
+	"M13": MajorThirteenth,
+	"m14": MinorFourteenth,
+	"M14": MajorFourteenth,
+	"P15": PerfectFifteenth,
+}
+
+// majorSemitones are the semitones of the perfect and major intervals from a
+// unison to a seventh, the intervals between the tonic and the degrees of the
+// major scale
+var majorSemitones = []int{0, 2, 4, 5, 7, 9, 11}
+
+// Semitones returns the interval of a number of semitones, negative to go down.
+// Within an octave, the interval is spelled as SpelledNote.Add has always done:
+// the tritone is a diminished fifth, and minor seconds, thirds, sixths and
+// sevenths are preferred to augmented unisons, seconds, fifths and sixths.
+func Semitones(n int) Interval {
+	if n < 0 {
+		return Semitones(-n).Descending()
+	}
+	return Interval{Steps: 7*(n/12) + intervalSteps[n%12], Semitones: n}
+}
+
+// ParseInterval parses an interval like ParseIntervalE, and panics if it is invalid
+func ParseInterval(s string) Interval {
+	interval, err := ParseIntervalE(s)
+	if err != nil {
+		panic(err)
+	}
+	return interval
+}
+
+// ParseIntervalE parses an interval written with its quality (P, M, m, A, d,
+// AA or dd) and number, such as "M9", "A4" or "d5", or as a scale degree
+// with accidentals relative to the major scale, such as "9", "#11" or "b13".
+// A leading - makes the interval descending. A diminished unison lowers a note
+// by a semitone, so it is the same interval as a descending augmented unison,
+// and String writes it "-A1".
+func ParseIntervalE(s string) (Interval, error) {
+	text, descending := strings.CutPrefix(s, "-")
+	end := strings.IndexFunc(text, func(r rune) bool { return r >= '0' && r <= '9' })
+	if end < 0 {
+		return Interval{}, fmt.Errorf("invalid interval %q: expected a number such as 3 or 9", s)
+	}
+	number, err := strconv.Atoi(text[end:])
+	if err != nil || number < 1 {
+		return Interval{}, fmt.Errorf("invalid interval %q: expected a number from 1 after %q", s, text[:end])
+	}
+	steps := number - 1
+	semitones := 12*(steps/7) + majorSemitones[steps%7]
+	perfect := isPerfectSteps(steps)
+
+	prefix := text[:end]
+	switch {
+	case prefix == "P" && perfect, prefix == "M" && !perfect:
+	case prefix == "m" && !perfect:
+		semitones--
+	case prefix != "" && strings.Trim(prefix, "A") == "":
+		semitones += len(prefix)
+	case prefix != "" && strings.Trim(prefix, "d") == "":
+		semitones -= len(prefix)
+		if !perfect {
+			semitones--
+		}
+	default:
+		accidental, offset := parseAccidentals(prefix)
+		if offset < len(prefix) {
+			return Interval{}, fmt.Errorf("invalid interval %q: unexpected %q, expected a quality (P, M, m, A, d) or accidentals", s, prefix)
+		}
+		semitones += accidental
+	}
+
+	interval := Interval{Steps: steps, Semitones: semitones}
+	if descending {
+		return interval.Descending(), nil
+	}
+	return interval, nil
+}
+
+// isPerfectSteps reports whether intervals of a number of steps are perfect
+// rather than major or minor, as unisons, fourths, fifths and octaves are
+func isPerfectSteps(steps int) bool {
+	simple := mod7(steps)
+	return simple == 0 || simple == 3 || simple == 4
+}
+
+// Number returns the diatonic size of the interval, 1 for a unison, 3 for a
+// third and 9 for a ninth, ascending or descending
+func (i Interval) Number() int {
+	return abs(i.Steps) + 1
+}
+
+// Quality returns P for perfect intervals, M for major, m for minor, and A or d
+// repeated for augmented or diminished intervals, as in AA4 or d7
+func (i Interval) Quality() string {
+	if i.isDescending() {
+		return i.Descending().Quality()
+	}
+	steps, semitones := i.Steps, i.Semitones
+	difference := semitones - 12*(steps/7) - majorSemitones[steps%7]
+	switch perfect := isPerfectSteps(steps); {
+	case difference == 0 && perfect:
+		return "P"
+	case difference == 0:
+		return "M"
+	case difference == -1 && !perfect:
+		return "m"
+	case difference > 0:
+		return strings.Repeat("A", difference)
+	case perfect:
+		return strings.Repeat("d", -difference)
+	}
+	return strings.Repeat("d", -difference-1)
+}
+
+// String writes the interval with its quality and number, such as "M9" or
+// "-P5" for a descending fifth. Unisons that lower a note, such as the
+// diminished unison, are written as descending augmented unisons like "-A1".
+func (i Interval) String() string {
+	if i.isDescending() {
+		return "-" + i.Descending().String()
+	}
+	return i.Quality() + strconv.Itoa(i.Number())
+}
+
+// Degree writes the interval as a scale degree with the accidentals taking the
+// major scale to it, such as "9", "#11" or "b13", as in chord symbols
+func (i Interval) Degree() string {
+	if i.isDescending() {
+		return "-" + i.Descending().Degree()
+	}
+	accidental := i.Semitones - 12*(i.Steps/7) - majorSemitones[i.Steps%7]
+	return strings.Repeat("#", max(0, accidental)) + strings.Repeat("b", max(0, -accidental)) + strconv.Itoa(i.Number())
+}
+
+// Add returns the sum of two intervals, as in a major ninth for a perfect fifth
+// and a perfect fifth
+func (i Interval) Add(other Interval) Interval {
+	return Interval{Steps: i.Steps + other.Steps, Semitones: i.Semitones + other.Semitones}
+}
+
+// Sub returns the interval left when going down another interval, as in a minor
+// third for a perfect fifth minus a major third
+func (i Interval) Sub(other Interval) Interval {
+	return i.Add(other.Descending())
+}
+
+// isDescending reports whether the interval goes down, which unisons do when
+// they lower the note
+func (i Interval) isDescending() bool {
+	return i.Steps < 0 || i.Steps == 0 && i.Semitones < 0
+}
+
+// Descending returns the same interval in the opposite direction
+func (i Interval) Descending() Interval {
+	return Interval{Steps: -i.Steps, Semitones: -i.Semitones}
+}
+
+// IsCompound reports whether the interval is larger than an octave
+func (i Interval) IsCompound() bool {
+	return abs(i.Steps) > 7
+}
+
+// Simple returns the interval reduced to less than an octave, as in a major
+// second for a major ninth. Octaves are reduced to unisons, and a diminished
+// octave to a descending augmented unison.
+func (i Interval) Simple() Interval {
+	if i.isDescending() {
+		return i.Descending().Simple().Descending()
+	}
+	octaves := i.Steps / 7
+	return Interval{Steps: i.Steps - 7*octaves, Semitones: i.Semitones - 12*octaves}
+}
+
+// Compound returns the interval an octave larger, as in a major ninth for a major second
+func (i Interval) Compound() Interval {
+	if i.isDescending() {
+		return i.Sub(PerfectOctave)
+	}
+	return i.Add(PerfectOctave)
+}
+
+// Invert returns the simple interval completing the interval to an octave, as
+// in a minor sixth for a major third or a diminished fifth for an augmented
+// fourth. Unisons and octaves invert to each other.
+func (i Interval) Invert() Interval {
+	if i.isDescending() {
+		return i.Descending().Invert().Descending()
+	}
+	simple := i.Simple()
+	if simple.Steps == 0 && i.Steps > 0 {
+		simple = simple.Add(PerfectOctave)
+	}
+	return PerfectOctave.Sub(simple)
+}
+
+// Between returns the interval from a pitch to another, descending when the
+// second pitch is lower, as in a major sixth from C4 up to A4
+func Between(from Pitch, to Pitch) Interval {
+	steps := 7*to.Octave + to.Note.letterIndex() - 7*from.Octave - from.Note.letterIndex()
+	return Interval{Steps: steps, Semitones: to.MIDI() - from.MIDI()}
+}
+
+// semitoneOffsets returns the semitones of intervals, the chord tones of a formula
+func semitoneOffsets(intervals []Interval) []int {
+	offsets := make([]int, len(intervals))
+	for i, interval := range intervals {
+		offsets[i] = interval.Semitones
+	}
+	return offsets
+}
+
+// mod7 returns n modulo 7 from 0 to 6, even for negative numbers
+func mod7(n int) int {
+	return ((n % 7) + 7) % 7
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
 }
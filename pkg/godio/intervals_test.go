@@ -0,0 +1,134 @@
+package godio
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestParseInterval(t *testing.T) {
+	parameters := []struct {
+		input     string
+		steps     int
+		semitones int
+		name      string
+		degree    string
+	}{
+		{"P1", 0, 0, "P1", "1"},
+		{"m3", 2, 3, "m3", "b3"},
+		{"A2", 1, 3, "A2", "#2"},
+		{"A4", 3, 6, "A4", "#4"},
+		{"d5", 4, 6, "d5", "b5"},
+		{"d7", 6, 9, "d7", "bb7"},
+		{"M9", 8, 14, "M9", "9"},
+		{"#11", 10, 18, "A11", "#11"},
+		{"b13", 12, 20, "m13", "b13"},
+		{"9", 8, 14, "M9", "9"},
+		{"AA4", 3, 7, "AA4", "##4"},
+		{"dd5", 4, 5, "dd5", "bb5"},
+		{"P15", 14, 24, "P15", "15"},
+		{"-P5", -4, -7, "-P5", "-5"},
+		{"-m2", -1, -1, "-m2", "-b2"},
+		{"d1", 0, -1, "-A1", "-#1"},
+		{"-A1", 0, -1, "-A1", "-#1"},
+	}
+
+	for _, p := range parameters {
+		t.Run(fmt.Sprintf("Testing %v", p.input), func(t *testing.T) {
+			interval, err := ParseIntervalE(p.input)
+			if err != nil {
+				t.Fatalf("Expected an interval, but got %v", err)
+			}
+			if interval != (Interval{Steps: p.steps, Semitones: p.semitones}) {
+				t.Errorf("Expected %d steps and %d semitones, but got %+v", p.steps, p.semitones, interval)
+			}
+			if interval.String() != p.name {
+				t.Errorf("Expected %v, but got %v", p.name, interval)
+			}
+			if interval.Degree() != p.degree {
+				t.Errorf("Expected the degree %v, but got %v", p.degree, interval.Degree())
+			}
+		})
+	}
+
+	for _, input := range []string{"", "M", "P3", "M5", "m4", "X5", "M0", "#"} {
+		if _, err := ParseIntervalE(input); err == nil {
+			t.Errorf("Expected an error for %q", input)
+		}
+	}
+	for name, interval := range IntervalNames {
+		if interval.String() != name {
+			t.Errorf("Expected IntervalNames[%q] to be named %v, but got %v", name, name, interval)
+		}
+	}
+}
+
+func TestIntervalArithmetic(t *testing.T) {
+	parameters := []struct {
+		name     string
+		actual   Interval
+		expected string
+	}{
+		{"M3 inverted", MajorThird.Invert(), "m6"},
+		{"A4 inverted", AugmentedFourth.Invert(), "d5"},
+		{"P1 inverted", PerfectUnison.Invert(), "P8"},
+		{"P8 inverted", PerfectOctave.Invert(), "P1"},
+		{"M9 inverted", MajorNinth.Invert(), "m7"},
+		{"P5+P5", PerfectFifth.Add(PerfectFifth), "M9"},
+		{"M3+m3", MajorThird.Add(MinorThird), "P5"},
+		{"M3+M3", MajorThird.Add(MajorThird), "A5"},
+		{"P5-M3", PerfectFifth.Sub(MajorThird), "m3"},
+		{"P4-P8", PerfectFourth.Sub(PerfectOctave), "-P5"},
+		{"M9 simple", MajorNinth.Simple(), "M2"},
+		{"A11 simple", AugmentedEleventh.Simple(), "A4"},
+		{"M2 compound", MajorSecond.Compound(), "M9"},
+		{"-M10 simple", MajorTenth.Descending().Simple(), "-M3"},
+		{"d8 simple", ParseInterval("d8").Simple(), "-A1"},
+		{"14 semitones", Semitones(14), "M9"},
+		{"6 semitones", Semitones(6), "d5"},
+		{"-3 semitones", Semitones(-3), "-m3"},
+	}
+
+	for _, p := range parameters {
+		t.Run(fmt.Sprintf("Testing %v", p.name), func(t *testing.T) {
+			if p.actual.String() != p.expected {
+				t.Errorf("Expected %v, but got %v", p.expected, p.actual)
+			}
+		})
+	}
+
+	if !MajorNinth.IsCompound() || PerfectOctave.IsCompound() {
+		t.Errorf("Expected only intervals larger than an octave to be compound")
+	}
+}
+
+func TestBetween(t *testing.T) {
+	parameters := []struct {
+		from     string
+		to       string
+		expected string
+	}{
+		{"C4", "A4", "M6"},
+		{"C4", "F#4", "A4"},
+		{"C4", "Gb4", "d5"},
+		{"E4", "G4", "m3"},
+		{"B3", "C4", "m2"},
+		{"C4", "D5", "M9"},
+		{"C4", "Eb5", "m10"},
+		{"G4", "C4", "-P5"},
+		{"A4", "F#4", "-m3"},
+		{"C4", "C4", "P1"},
+	}
+
+	for _, p := range parameters {
+		t.Run(fmt.Sprintf("Testing %v to %v", p.from, p.to), func(t *testing.T) {
+			from, to := ParsePitch(p.from), ParsePitch(p.to)
+			interval := Between(from, to)
+			if interval.String() != p.expected {
+				t.Errorf("Expected %v, but got %v", p.expected, interval)
+			}
+			if reached := from.Add(interval); reached != to {
+				t.Errorf("Expected %v to reach %v, but got %v", p.from, p.to, reached)
+			}
+		})
+	}
+}
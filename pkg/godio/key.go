@@ -0,0 +1,110 @@
+package godio
+
+import (
+	"fmt"
+	"strings"
+)
+
+type KeyMode string
+
+const (
+	ModeMajor KeyMode = "major"
+	ModeMinor KeyMode = "minor"
+)
+
+// Key is a tonal center such as E♭ major or A minor
+type Key struct {
+	Tonic SpelledNote
+	Mode  KeyMode
+}
+
+// scaleDegrees are the semitones above the tonic of the seven degrees of each mode.
+// Minor keys use the natural minor scale.
+var scaleDegrees = map[KeyMode][]int{
+	ModeMajor: {0, 2, 4, 5, 7, 9, 11},
+	ModeMinor: {0, 2, 3, 5, 7, 8, 10},
+}
+
+// minorKeyWords are the suffixes marking a minor key, longest first
+var minorKeyWords = []string{"minor", "min", "m", "-"}
+
+// majorKeyWords are the suffixes marking a major key, longest first
+var majorKeyWords = []string{"major", "maj", "M", ""}
+
+// ParseKey parses a key such as "Eb", "Eb major", "F#m", "A minor" or "c" where a
+// lowercase tonic stands for a minor key.
+func ParseKey(s string) (Key, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return Key{}, fmt.Errorf("invalid key %q: expected a tonic (A-G)", s)
+	}
+
+	key := Key{Mode: ModeMajor}
+	if s[0] >= 'a' && s[0] <= 'g' {
+		key.Mode = ModeMinor
+		s = strings.ToUpper(s[:1]) + s[1:]
+	}
+
+	tonicLength := 1
+	for tonicLength < len(s) && (s[tonicLength] == '#' || s[tonicLength] == 'b') {
+		tonicLength++
+	}
+	tonic, err := ParseNoteE(s[:tonicLength])
+	if err != nil {
+		return Key{}, fmt.Errorf("invalid key %q: unknown tonic %q", s, s[:tonicLength])
+	}
+	key.Tonic = tonic
+
+	mode := strings.TrimSpace(s[tonicLength:])
+	switch {
+	case lookupKeyWord(minorKeyWords, mode):
+		key.Mode = ModeMinor
+	case lookupKeyWord(majorKeyWords, mode):
+	default:
+		return Key{}, fmt.Errorf("invalid key %q: unknown mode %q", s, mode)
+	}
+	return key, nil
+}
+
+// lookupKeyWord reports whether mode is one of the words, ignoring case for words longer than a letter
+func lookupKeyWord(words []string, mode string) bool {
+	for _, word := range words {
+		if mode == word || len(word) > 1 && strings.EqualFold(mode, word) {
+			return true
+		}
+	}
+	return false
+}
+
+func (k Key) String() string {
+	return fmt.Sprintf("%s %s", k.Tonic, k.Mode)
+}
+
+// degreeNote returns the note on a scale degree from 1 to 7, raised or lowered
+// by a number of semitones. The third, sixth and seventh of minor keys are
+// already lowered, so lowering them again names the same note as in the
+// parallel major key, as in bVI for Ab in C minor. The note is spelled with the
+// letter of the degree, or with sharps when that would need more than two accidentals.
+func (k Key) degreeNote(degree int, accidental int) SpelledNote {
+	semitones := scaleDegrees[k.Mode][degree-1]
+	if major := scaleDegrees[ModeMajor][degree-1]; accidental < 0 && semitones < major {
+		semitones = major
+	}
+	pitchClass := k.Tonic.PitchClass().Add(Semitones(semitones + accidental))
+	return spellPitchClass((k.Tonic.letterIndex()+degree-1)%7, pitchClass)
+}
+
+// MarshalText writes the key as String does, so that keys appear as "Eb major" in JSON
+func (k Key) MarshalText() ([]byte, error) {
+	return []byte(k.String()), nil
+}
+
+// UnmarshalText parses a key written as accepted by ParseKey
+func (k *Key) UnmarshalText(text []byte) error {
+	key, err := ParseKey(string(text))
+	if err != nil {
+		return err
+	}
+	*k = key
+	return nil
+}
@@ -0,0 +1,136 @@
+package godio
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// keyboardWhiteKeys are the pitch classes of the white keys of an octave
+var keyboardWhiteKeys = []int{0, 2, 4, 5, 7, 9, 11}
+
+// keyboardOctaves returns the MIDI notes of the first C and last B of the
+// octaves covering the notes, or the octave of middle C when there are none.
+func keyboardOctaves(notes []int) (int, int) {
+	if len(notes) == 0 {
+		return 60, 71
+	}
+	first := slices.Min(notes) - mod12(slices.Min(notes))
+	last := slices.Max(notes) - mod12(slices.Max(notes)) + 11
+	return first, last
+}
+
+// whiteKeyIndex returns the index of the white key of a MIDI note counted from
+// the first C, or of the white key on the left of a black key.
+func whiteKeyIndex(note int, first int) int {
+	octave, pitchClass := floorDiv(note-first, 12), mod12(note)
+	index := 0
+	for i, white := range keyboardWhiteKeys {
+		if white <= pitchClass {
+			index = i
+		}
+	}
+	return 7*octave + index
+}
+
+// isBlackKey reports whether a MIDI note is played on a black key
+func isBlackKey(note int) bool {
+	return !slices.Contains(keyboardWhiteKeys, mod12(note))
+}
+
+// KeyboardDiagram draws the notes, given as MIDI note numbers, on an ASCII piano
+// keyboard covering their octaves. Pressed white keys are marked with * and
+// pressed black keys with #*#, and each C is labelled with its octave.
+func KeyboardDiagram(notes []int) string {
+	first, last := keyboardOctaves(notes)
+	whites := 7 * (last - first + 1) / 12
+	width := 4*whites + 1
+
+	rows := make([][]byte, 4)
+	for i := range rows {
+		rows[i] = []byte(strings.Repeat(" ", width))
+		for column := 0; column < width; column += 4 {
+			rows[i][column] = '|'
+		}
+	}
+	for column := 1; column < width; column++ {
+		if column%4 != 0 {
+			rows[3][column] = '_'
+		}
+	}
+
+	for note := first; note <= last; note++ {
+		pressed := slices.Contains(notes, note)
+		if !isBlackKey(note) {
+			if pressed {
+				rows[2][4*whiteKeyIndex(note, first)+2] = '*'
+			}
+			continue
+		}
+		// Black keys lie over the line on the right of the white key below them
+		boundary := 4 * (whiteKeyIndex(note, first) + 1)
+		copy(rows[0][boundary-1:], "###")
+		copy(rows[1][boundary-1:], "###")
+		if pressed {
+			rows[1][boundary] = '*'
+		}
+	}
+
+	labels := []byte(strings.Repeat(" ", width))
+	for octave := first; octave < last; octave += 12 {
+		copy(labels[4*whiteKeyIndex(octave, first)+1:], PitchFromMIDI(octave).String())
+	}
+
+	var b strings.Builder
+	for _, row := range append(rows, labels) {
+		b.WriteString(strings.TrimRight(string(row), " "))
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+// KeyboardSVG draws the notes, given as MIDI note numbers, on an SVG piano
+// keyboard covering their octaves, with the pressed keys highlighted and named.
+func KeyboardSVG(notes []int) string {
+	const (
+		whiteWidth, whiteHeight = 24, 120
+		blackWidth, blackHeight = 14, 75
+		margin                  = 4
+	)
+	first, last := keyboardOctaves(notes)
+	whites := 7 * (last - first + 1) / 12
+	width, height := whites*whiteWidth+2*margin, whiteHeight+2*margin+32
+
+	var b strings.Builder
+	fmt.Fprintf(&b, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d">`+"\n", width, height, width, height)
+	fill := func(note int, color string) string {
+		if slices.Contains(notes, note) {
+			return "#e8743b"
+		}
+		return color
+	}
+	// Names of black keys are written on a second line so that they do not overlap
+	label := func(note int, x int, line int) {
+		if slices.Contains(notes, note) {
+			fmt.Fprintf(&b, `  <text x="%d" y="%d" font-family="sans-serif" font-size="10" text-anchor="middle">%s</text>`+"\n", x, margin+whiteHeight+14*line, PitchFromMIDI(note))
+		}
+	}
+
+	// White keys are drawn first so that the black keys cover them
+	for note := first; note <= last; note++ {
+		if !isBlackKey(note) {
+			x := margin + whiteKeyIndex(note, first)*whiteWidth
+			fmt.Fprintf(&b, `  <rect x="%d" y="%d" width="%d" height="%d" fill="%s" stroke="black"/>`+"\n", x, margin, whiteWidth, whiteHeight, fill(note, "white"))
+			label(note, x+whiteWidth/2, 1)
+		}
+	}
+	for note := first; note <= last; note++ {
+		if isBlackKey(note) {
+			x := margin + (whiteKeyIndex(note, first)+1)*whiteWidth - blackWidth/2
+			fmt.Fprintf(&b, `  <rect x="%d" y="%d" width="%d" height="%d" fill="%s" stroke="black"/>`+"\n", x, margin, blackWidth, blackHeight, fill(note, "black"))
+			label(note, x+blackWidth/2, 2)
+		}
+	}
+	b.WriteString("</svg>\n")
+	return b.String()
+}
@@ -0,0 +1,57 @@
+package godio
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestKeyboardDiagram(t *testing.T) {
+	parameters := []struct {
+		notes    string
+		expected string
+	}{
+		{"C4 E4 G4", "" +
+			"|  ### ###  |  ### ### ###  |\n" +
+			"|  ### ###  |  ### ### ###  |\n" +
+			"| * |   | * |   | * |   |   |\n" +
+			"|___|___|___|___|___|___|___|\n" +
+			" C4\n"},
+		{"Bb3 C#4", "" +
+			"|  ### ###  |  ### ### ###  |  ### ###  |  ### ### ###  |\n" +
+			"|  ### ###  |  ### ### #*#  |  #*# ###  |  ### ### ###  |\n" +
+			"|   |   |   |   |   |   |   |   |   |   |   |   |   |   |\n" +
+			"|___|___|___|___|___|___|___|___|___|___|___|___|___|___|\n" +
+			" C3                          C4\n"},
+	}
+
+	for _, p := range parameters {
+		t.Run(fmt.Sprintf("Testing %v", p.notes), func(t *testing.T) {
+			notes := []int{}
+			for _, name := range strings.Fields(p.notes) {
+				notes = append(notes, ParsePitch(name).MIDI())
+			}
+			if diagram := KeyboardDiagram(notes); diagram != p.expected {
+				t.Errorf("Expected\n%v, but got\n%v", p.expected, diagram)
+			}
+		})
+	}
+}
+
+func TestKeyboardSVG(t *testing.T) {
+	svg := KeyboardSVG([]int{ParsePitch("C4").MIDI(), ParsePitch("Eb4").MIDI(), ParsePitch("G5").MIDI()})
+	if !strings.HasPrefix(svg, "<svg") || !strings.HasSuffix(svg, "</svg>\n") {
+		t.Errorf("Expected an SVG document, but got %v", svg)
+	}
+	if keys := strings.Count(svg, "<rect"); keys != 24 {
+		t.Errorf("Expected 24 keys, but got %v", keys)
+	}
+	if pressed := strings.Count(svg, `fill="#e8743b"`); pressed != 3 {
+		t.Errorf("Expected 3 pressed keys, but got %v", pressed)
+	}
+	for _, name := range []string{">C4<", ">D#4<", ">G5<"} {
+		if !strings.Contains(svg, name) {
+			t.Errorf("Expected the label %v, but got %v", name, svg)
+		}
+	}
+}
@@ -0,0 +1,118 @@
+package godio
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+type tokenKind int
+
+const (
+	tokenEOF    tokenKind = iota
+	tokenNote             // Note letter from A to G
+	tokenSharp            // #
+	tokenFlat             // b
+	tokenNumber           // Run of digits such as 7 or 13
+	tokenWord             // Keyword such as maj or sus, or an unknown run of letters
+	tokenSymbol           // Any other single character
+	tokenLParen           // (
+	tokenRParen           // )
+	tokenComma            // ,
+	tokenSlash            // /
+	tokenPipe             // |
+)
+
+// token is a lexical unit of a chord symbol along with its byte offsets in the symbol.
+// The text of accidentals is normalized so that ♯ and ♭ read as # and b.
+type token struct {
+	kind   tokenKind
+	text   string
+	offset int
+	end    int
+}
+
+// chordWords are the keywords recognized inside chord symbols, longest first so
+// that "maj" is not lexed as "m" followed by "aj".
+var chordWords = []string{"omit", "maj", "min", "dim", "aug", "sus", "add", "alt", "mi", "no", "m"}
+
+// lexChord splits a chord symbol into tokens. Whitespace is skipped and the
+// returned slice always ends with a tokenEOF.
+func lexChord(input string) []token {
+	tokens := []token{}
+	for offset := 0; offset < len(input); {
+		char, size := utf8.DecodeRuneInString(input[offset:])
+		kind := tokenSymbol
+		length := size
+		text := ""
+
+		switch {
+		case unicode.IsSpace(char):
+			offset += size
+			continue
+		case char >= 'A' && char <= 'G':
+			kind = tokenNote
+		case char == '#' || char == '♯':
+			kind = tokenSharp
+			text = "#"
+		case char == 'b' || char == '♭':
+			kind = tokenFlat
+			text = "b"
+		case char == '(':
+			kind = tokenLParen
+		case char == ')':
+			kind = tokenRParen
+		case char == ',':
+			kind = tokenComma
+		case char == '/':
+			kind = tokenSlash
+		case char == '|':
+			kind = tokenPipe
+		case unicode.IsDigit(char):
+			kind = tokenNumber
+			length = strings.IndexFunc(input[offset:], func(r rune) bool { return !unicode.IsDigit(r) })
+			if length < 0 {
+				length = len(input) - offset
+			}
+		case unicode.IsLetter(char):
+			kind = tokenWord
+			length = wordLength(input[offset:])
+		}
+
+		if text == "" {
+			text = input[offset : offset+length]
+		}
+		tokens = append(tokens, token{kind: kind, text: text, offset: offset, end: offset + length})
+		offset += length
+	}
+	return append(tokens, token{kind: tokenEOF, offset: len(input), end: len(input)})
+}
+
+// wordLength returns the length of the word at the start of s. Keywords are
+// matched first, otherwise the word runs until a keyword, a flat or a non-letter.
+func wordLength(s string) int {
+	for _, word := range chordWords {
+		if strings.HasPrefix(s, word) {
+			return len(word)
+		}
+	}
+	length := 0
+	for length < len(s) {
+		char, size := utf8.DecodeRuneInString(s[length:])
+		if !unicode.IsLetter(char) || (length > 0 && (char == 'b' || char >= 'A' && char <= 'G' || startsWithWord(s[length:]))) {
+			break
+		}
+		length += size
+	}
+	return length
+}
+
+// startsWithWord reports whether s starts with one of the chordWords
+func startsWithWord(s string) bool {
+	for _, word := range chordWords {
+		if strings.HasPrefix(s, word) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,81 @@
+package godio
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/samber/lo"
+)
+
+// WithTopNote is like WithTopNoteE but panics if the melody note cannot be added
+// to the chord. It simplifies the use of melody notes that are known to fit.
+func (c Chord) WithTopNote(melody Pitch) *Chord {
+	chord, err := c.WithTopNoteE(melody)
+	if err != nil {
+		panic(err)
+	}
+	return chord
+}
+
+// WithTopNoteE returns a copy of the chord voiced under a melody note, which is
+// always the highest voice. The melody tone is moved to the end of the chord
+// tones. When it is not a chord tone, an omitted chord tone is restored, and any
+// other tone is added as an extension named by tensionNames, such as the 9 of C7
+// under a D, or as the 7 of a chord without a seventh. Tones an octave or more
+// above the melody are voiced below it. An error is returned for melody notes
+// that cannot be named, such as the major third of a minor chord.
+func (c Chord) WithTopNoteE(melody Pitch) (*Chord, error) {
+	tone := mod12(int(melody.Note.PitchClass()) - int(c.Root.PitchClass()))
+	isTone := func(t int) bool { return mod12(t) == tone }
+
+	if !slices.ContainsFunc(c.Tones, isTone) {
+		formula := append([]int{0}, semitoneOffsets(DefaultChordRegistry.formula(c.Quality))...)
+		omission, omitted := lo.Find(c.Omissions, func(omission string) bool {
+			return slices.Contains(omissionFormulas[omission], tone) && slices.ContainsFunc(formula, isTone)
+		})
+		tension, named := tensionNames[tone]
+		switch {
+		case omitted:
+			c.Omissions = lo.Without(c.Omissions, omission)
+		case named || tone == MinorSeventh.Semitones:
+			if !named {
+				tension = "7"
+			}
+			c.Extensions = append(slices.Clone(c.Extensions), tension)
+			tone = extensionFormulas[tension].Semitones
+		default:
+			return nil, fmt.Errorf("cannot voice %v under %v, which is not a chord tone or a tension", c, melody)
+		}
+	}
+	c.Tones = append(slices.DeleteFunc(slices.Clone(c.Tones), isTone), tone)
+	c.Constraints.TopNote = melody.MIDI()
+	return &c, nil
+}
+
+// keepMelodyTone adds back the melody tone of the TopNote constraint when a
+// voicing rule removed it
+func (c *Chord) keepMelodyTone() {
+	if c.Constraints.TopNote == 0 {
+		return
+	}
+	tone := mod12(c.Constraints.TopNote - int(c.Root.PitchClass()))
+	if !slices.ContainsFunc(c.Tones, func(t int) bool { return mod12(t) == tone }) {
+		c.addTone(tone)
+	}
+}
+
+// placeTopNote voices the upper notes of a voicing under the top note: notes of
+// its pitch class are replaced by it and notes above it are lowered by octaves.
+func placeTopNote(notes []int, top int) []int {
+	placed := []int{}
+	for _, note := range notes {
+		if mod12(note) == mod12(top) {
+			continue
+		}
+		for note > top {
+			note -= 12
+		}
+		placed = append(placed, note)
+	}
+	return sortedUnique(append(placed, top))
+}
@@ -0,0 +1,84 @@
+package godio
+
+import (
+	"fmt"
+	"slices"
+	"testing"
+)
+
+func TestWithTopNote(t *testing.T) {
+	parameters := []struct {
+		chord      string
+		melody     string
+		extensions []string
+		tones      []int
+	}{
+		{"C7", "D5", []string{"9"}, []int{0, 4, 7, 10, 2}},
+		{"Cmaj7", "E5", []string{}, []int{0, 7, 11, 4}},
+		{"Am7", "F#5", []string{"13"}, []int{0, 3, 7, 10, 9}},
+		{"C", "Bb4", []string{"7"}, []int{0, 4, 7, 10}},
+		{"C(no5)", "G4", []string{}, []int{0, 4, 7}},
+		{"Cm11", "G-1", []string{}, []int{0, 3, 10, 14, -7, 7}},
+	}
+
+	for _, p := range parameters {
+		t.Run(fmt.Sprintf("Testing %v under %v", p.chord, p.melody), func(t *testing.T) {
+			chord := MustParseChord(p.chord)
+			melody := ParsePitch(p.melody)
+			voiced := chord.WithTopNote(melody)
+			if !slices.Equal(voiced.Extensions, p.extensions) {
+				t.Errorf("Expected the extensions %v, but got %v", p.extensions, voiced.Extensions)
+			}
+			if !slices.Equal(voiced.Tones, p.tones) {
+				t.Errorf("Expected the tones %v, but got %v", p.tones, voiced.Tones)
+			}
+			if len(chord.Extensions) > 0 {
+				t.Errorf("Expected the chord to be kept, but got %v", chord.Extensions)
+			}
+
+			for _, frequencies := range [][]float64{voiced.GetFrequencies(), voiced.GetFrequenciesV2()} {
+				if top := slices.Max(frequencies); top != melody.Frequency() {
+					t.Errorf("Expected %v on top, but got %v", melody, PitchFromMIDI(StandardTuning.Note(top)))
+				}
+			}
+			for _, style := range []VoicingStyle{VoicingClose, VoicingDrop2, VoicingShell} {
+				if voicing := voiced.Voice(style); slices.Max(voicing) != melody.MIDI() {
+					t.Errorf("Expected %v on top of the %v voicing, but got %v", melody, style, pitchNames(voicing))
+				}
+			}
+		})
+	}
+}
+
+func TestWithTopNoteErrors(t *testing.T) {
+	parameters := []struct {
+		chord  string
+		melody string
+	}{
+		{"Cm", "E5"},
+		{"Csus4", "E5"},
+		{"Cdim", "G4"},
+	}
+
+	for _, p := range parameters {
+		t.Run(fmt.Sprintf("Testing %v under %v", p.chord, p.melody), func(t *testing.T) {
+			if _, err := MustParseChord(p.chord).WithTopNoteE(ParsePitch(p.melody)); err == nil {
+				t.Errorf("Expected an error, but got nil")
+			}
+		})
+	}
+}
+
+func TestVoicerTopNote(t *testing.T) {
+	chords := []*Chord{
+		MustParseChord("Dm7").WithTopNote(ParsePitch("F4")),
+		MustParseChord("G7").WithTopNote(ParsePitch("E4")),
+		MustParseChord("Cmaj7").WithTopNote(ParsePitch("D4")),
+	}
+	expected := []string{"D3 A3 C4 D4 F4", "G2 F3 B3 D4 E4", "C3 E3 G3 B3 D4"}
+	for i, voicing := range NewVoicer().Voice(chords) {
+		if names := pitchNames(voicing); names != expected[i] {
+			t.Errorf("Expected %v, but got %v", expected[i], names)
+		}
+	}
+}
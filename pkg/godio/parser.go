@@ -0,0 +1,323 @@
+package godio
+
+import "strings"
+
+// ChordSymbol is the syntax tree of a chord symbol. It is produced by
+// ParseChordSymbol and turned into a Chord by ParseChordE.
+type ChordSymbol struct {
+	Root        string       // Root note as written, e.g. "Bb"
+	Quality     string       // Key of the chord formula, e.g. "m7"
+	Alterations []string     // Extensions following the quality, e.g. "b5" or "sus4"
+	Additions   []string     // Extensions introduced by "add", e.g. "9" for "add9"
+	Omissions   []string     // Chord degrees removed by "omit" or "no", e.g. "3"
+	Bass        string       // Bass note as written, empty when the root is in the bass
+	Upper       *ChordSymbol // Upper structure of a polychord such as "Eb|C"
+}
+
+// omissionFormulas maps the chord degrees that can be omitted to the formula tones they remove
+var omissionFormulas = map[string][]int{
+	"1": {0},
+	"3": {3, 4},
+	"5": {6, 7, 8},
+}
+
+// qualitySymbols maps the symbols and abbreviations found on charts to the text
+// they stand for in chord qualities. Δ and ^ are resolved by ChordRegistry.lookup.
+var qualitySymbols = map[string]string{
+	"-":   "m",
+	"mi":  "m",
+	"min": "m",
+	"M":   "maj",
+	"Maj": "maj",
+	"^":   "Δ",
+	"°":   "dim",
+	"o":   "dim",
+	"+":   "aug",
+}
+
+// qualityAliases maps spellings of chord qualities that are not keys of chordFormulas to
+// their key. They are the built-in aliases of NewChordRegistry.
+var qualityAliases = map[string]string{
+	"Δ":      "maj7",
+	"mΔ":     "mmaj7",
+	"ø":      "ø7",
+	"7aug":   "aug7",
+	"maj7#5": "augmaj7",
+}
+
+// ParseChordSymbol parses a chord symbol into its syntax tree following this grammar:
+//
+//	symbol    = chord [ ( "|" | "/" ) chord ] [ "/" note ]
+//	chord     = note quality { modifier }
+//	note      = letter { "#" | "b" }
+//	modifier  = ( "#" | "b" | "+" | "-" ) number | "sus" [ number ] | "alt" | "add" [ "#" | "b" ] number
+//	          | ( "omit" | "no" ) number | "(" item { [ "," ] item } ")"
+//	item      = modifier | number | "maj" number
+//
+// In a polychord the first chord is the upper structure. A slash is followed by
+// the lower chord of an upper structure when a quality or modifiers follow the
+// note, as in "D/C7", and by a bass note otherwise, as in "C/E". Six-nine chords
+// may be written 6/9. A *ParseError is returned
+// when the symbol does not follow the grammar or uses an unknown quality or extension.
+func ParseChordSymbol(input string) (*ChordSymbol, error) {
+	p := &chordParser{input: input, tokens: lexChord(input)}
+
+	symbol, err := p.parseChord()
+	if err != nil {
+		return nil, err
+	}
+	if p.peek().kind == tokenPipe || p.slashChord() {
+		p.next()
+		lower, err := p.parseChord()
+		if err != nil {
+			return nil, err
+		}
+		lower.Upper = symbol
+		symbol = lower
+	}
+	if p.peek().kind == tokenSlash {
+		p.next()
+		bass, err := p.parseNote("a bass note (A-G)")
+		if err != nil {
+			return nil, err
+		}
+		symbol.Bass = bass
+	}
+	if tok := p.peek(); tok.kind != tokenEOF {
+		return nil, p.errorAt(tok, "the end of the chord symbol")
+	}
+	return symbol, nil
+}
+
+// chordParser is a recursive descent parser over the tokens of a chord symbol
+type chordParser struct {
+	input  string
+	tokens []token
+	pos    int
+}
+
+func (p *chordParser) peek() token {
+	return p.tokens[p.pos]
+}
+
+func (p *chordParser) next() token {
+	tok := p.tokens[p.pos]
+	if tok.kind != tokenEOF {
+		p.pos++
+	}
+	return tok
+}
+
+// errorAt returns a ParseError reporting tok as unexpected
+func (p *chordParser) errorAt(tok token, expected string) *ParseError {
+	return &ParseError{Input: p.input, Offset: tok.offset, Token: p.input[tok.offset:tok.end], Expected: expected}
+}
+
+// errorSpan returns a ParseError reporting the tokens from start up to the current position as unexpected
+func (p *chordParser) errorSpan(start int, expected string) *ParseError {
+	first := p.tokens[start]
+	last := p.tokens[p.pos-1]
+	return &ParseError{Input: p.input, Offset: first.offset, Token: p.input[first.offset:last.end], Expected: expected}
+}
+
+// textFrom returns the normalized text of the tokens from start up to the current position
+func (p *chordParser) textFrom(start int) string {
+	text := ""
+	for _, tok := range p.tokens[start:p.pos] {
+		text += tok.text
+	}
+	return text
+}
+
+// slashChord reports whether the next tokens are a slash followed by a lower chord
+// rather than by a bass note, which is only followed by the end or by another slash
+func (p *chordParser) slashChord() bool {
+	start := p.pos
+	defer func() { p.pos = start }()
+	if p.next().kind != tokenSlash {
+		return false
+	}
+	if _, err := p.parseNote(""); err != nil {
+		return false
+	}
+	next := p.peek().kind
+	return next != tokenEOF && next != tokenSlash
+}
+
+func (p *chordParser) parseChord() (*ChordSymbol, error) {
+	root, err := p.parseNote("a root note (A-G)")
+	if err != nil {
+		return nil, err
+	}
+	symbol := &ChordSymbol{Root: root, Quality: p.parseQuality()}
+
+	for {
+		found, err := p.parseModifier(symbol, false)
+		if err != nil {
+			return nil, err
+		}
+		if !found {
+			break
+		}
+	}
+
+	switch tok := p.peek(); tok.kind {
+	case tokenEOF, tokenSlash, tokenPipe:
+		return symbol, nil
+	default:
+		if symbol.Quality == "" {
+			return nil, p.errorAt(tok, "a chord quality")
+		}
+		return nil, p.errorAt(tok, "a chord extension")
+	}
+}
+
+func (p *chordParser) parseNote(expected string) (string, error) {
+	start := p.pos
+	if p.next().kind != tokenNote {
+		p.pos = start
+		return "", p.errorAt(p.peek(), expected)
+	}
+	for p.peek().kind == tokenSharp || p.peek().kind == tokenFlat {
+		p.next()
+	}
+	note := p.textFrom(start)
+	if !isNoteName(note) {
+		return "", p.errorSpan(start, expected)
+	}
+	return note, nil
+}
+
+// parseQuality consumes the longest run of tokens that spells a quality of
+// DefaultChordRegistry. The major triad, spelled as an empty quality, always
+// matches. A slash between numbers is skipped so that 6/9 reads as 69, and a
+// "+" or "-" followed by a number ends the quality unless it starts it.
+func (p *chordParser) parseQuality() string {
+	quality, length := "", 0
+	text := ""
+	for i := p.pos; i < len(p.tokens); i++ {
+		tok := p.tokens[i]
+		if tok.kind == tokenSlash && p.tokens[i+1].kind == tokenNumber && strings.HasSuffix(text, "6") {
+			// The slash of 6/9 and m6/9 is left out of the quality
+			continue
+		}
+		if tok.kind == tokenEOF || tok.kind == tokenSlash || tok.kind == tokenPipe {
+			break
+		}
+		if i > p.pos && (tok.text == "+" || tok.text == "-") && p.tokens[i+1].kind == tokenNumber {
+			// A sign followed by a number after the start of the quality is a modifier, as in C7+5
+			break
+		}
+		if symbol, ok := qualitySymbols[tok.text]; ok {
+			text += symbol
+		} else {
+			text += tok.text
+		}
+		if key, ok := DefaultChordRegistry.lookup(text); ok {
+			quality, length = key, i-p.pos+1
+		}
+	}
+	p.pos += length
+	return quality
+}
+
+// parseModifier parses a single modifier into symbol and reports whether one was found.
+// Bare numbers and "maj" are only extensions when inGroup is set.
+func (p *chordParser) parseModifier(symbol *ChordSymbol, inGroup bool) (bool, error) {
+	start := p.pos
+	tok := p.peek()
+	switch {
+	case tok.kind == tokenSharp || tok.kind == tokenFlat:
+		p.next()
+		extension, err := p.parseExtension(start, tok.text)
+		if err != nil {
+			return false, err
+		}
+		symbol.Alterations = append(symbol.Alterations, extension)
+	case tok.kind == tokenNumber && inGroup:
+		extension, err := p.parseExtension(start, "")
+		if err != nil {
+			return false, err
+		}
+		symbol.Alterations = append(symbol.Alterations, extension)
+	case tok.kind == tokenSymbol && (tok.text == "+" || tok.text == "-") && p.tokens[p.pos+1].kind == tokenNumber:
+		// Older charts write raised and lowered extensions as +5 or -9
+		p.next()
+		extension, err := p.parseExtension(start, map[string]string{"+": "#", "-": "b"}[tok.text])
+		if err != nil {
+			return false, err
+		}
+		symbol.Alterations = append(symbol.Alterations, extension)
+	case tok.kind == tokenWord && (tok.text == "sus" || tok.text == "alt" || tok.text == "maj" && inGroup):
+		p.next()
+		if tok.text != "alt" && p.peek().kind == tokenNumber {
+			p.next()
+		}
+		extension := p.textFrom(start)
+		if !isExtension(extension) {
+			return false, p.errorSpan(start, "a chord extension")
+		}
+		symbol.Alterations = append(symbol.Alterations, extension)
+	case tok.kind == tokenWord && tok.text == "add":
+		p.next()
+		accidental := ""
+		if next := p.peek(); next.kind == tokenSharp || next.kind == tokenFlat {
+			accidental = p.next().text
+		}
+		extension, err := p.parseExtension(start, accidental)
+		if err != nil {
+			return false, err
+		}
+		symbol.Additions = append(symbol.Additions, extension)
+	case tok.kind == tokenWord && (tok.text == "omit" || tok.text == "no"):
+		p.next()
+		degree := p.next()
+		if _, ok := omissionFormulas[degree.text]; degree.kind != tokenNumber || !ok {
+			return false, p.errorAt(degree, "an omitted degree (1, 3 or 5)")
+		}
+		symbol.Omissions = append(symbol.Omissions, degree.text)
+	case tok.kind == tokenLParen:
+		p.next()
+		if err := p.parseGroup(symbol); err != nil {
+			return false, err
+		}
+	default:
+		return false, nil
+	}
+	return true, nil
+}
+
+// parseExtension parses the degree of an extension that starts at token start
+// and checks it against extensionFormulas.
+func (p *chordParser) parseExtension(start int, accidental string) (string, error) {
+	degree := p.peek()
+	if degree.kind != tokenNumber {
+		return "", p.errorAt(degree, "an extension degree")
+	}
+	p.next()
+	extension := accidental + degree.text
+	if !isExtension(extension) {
+		return "", p.errorSpan(start, "a chord extension")
+	}
+	return extension, nil
+}
+
+// parseGroup parses the items of a parenthesized group after its opening parenthesis.
+// Groups may be nested.
+func (p *chordParser) parseGroup(symbol *ChordSymbol) error {
+	for {
+		found, err := p.parseModifier(symbol, true)
+		if err != nil {
+			return err
+		}
+		switch tok := p.peek(); {
+		case tok.kind == tokenRParen:
+			p.next()
+			return nil
+		case tok.kind == tokenComma:
+			p.next()
+		case !found:
+			return p.errorAt(tok, "a chord extension or \")\"")
+		}
+	}
+}
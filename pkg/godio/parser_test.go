@@ -0,0 +1,69 @@
+package godio
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+func TestParseChordSymbol(t *testing.T) {
+	parameters := []struct {
+		input    string
+		expected ChordSymbol
+	}{
+		{"Cm7b5/Gb", ChordSymbol{Root: "C", Quality: "m7", Alterations: []string{"b5"}, Bass: "Gb"}},
+		{"Cm(maj7)", ChordSymbol{Root: "C", Quality: "m", Alterations: []string{"maj7"}}},
+		{"C7(b9(#11))", ChordSymbol{Root: "C", Quality: "7", Alterations: []string{"b9", "#11"}}},
+		{"C7sus4add13", ChordSymbol{Root: "C", Quality: "7", Alterations: []string{"sus4"}, Additions: []string{"13"}}},
+		{"Bb(omit3)", ChordSymbol{Root: "Bb", Omissions: []string{"3"}}},
+		{"Gno5", ChordSymbol{Root: "G", Omissions: []string{"5"}}},
+		{"Eb|C7/G", ChordSymbol{Root: "C", Quality: "7", Bass: "G", Upper: &ChordSymbol{Root: "Eb"}}},
+		{"C6/9", ChordSymbol{Root: "C", Quality: "69"}},
+		{"Cm6/9/G", ChordSymbol{Root: "C", Quality: "m69", Bass: "G"}},
+		{"Cadd2add4", ChordSymbol{Root: "C", Additions: []string{"2", "4"}}},
+		{"C7(omit5)", ChordSymbol{Root: "C", Quality: "7", Omissions: []string{"5"}}},
+		{"D/C7", ChordSymbol{Root: "C", Quality: "7", Upper: &ChordSymbol{Root: "D"}}},
+		{"Ebm/Bbm7/F", ChordSymbol{Root: "Bb", Quality: "m7", Bass: "F", Upper: &ChordSymbol{Root: "Eb", Quality: "m"}}},
+		{"D/C", ChordSymbol{Root: "D", Bass: "C"}},
+	}
+
+	for _, p := range parameters {
+		t.Run(fmt.Sprintf("Testing %v", p.input), func(t *testing.T) {
+			symbol, err := ParseChordSymbol(p.input)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(*symbol, p.expected) {
+				t.Errorf("Expected %+v, but got %+v", p.expected, *symbol)
+			}
+		})
+	}
+}
+
+func TestParseChordSymbolErrors(t *testing.T) {
+	parameters := []struct {
+		input  string
+		offset int
+		token  string
+	}{
+		{"C7(b9", 5, ""},
+		{"Cmaj7/G/B", 7, "/"},
+		{"C(no7)", 4, "7"},
+		{"C7,", 2, ","},
+		{"C/9", 2, "9"},
+		{"C/13", 2, "13"},
+	}
+
+	for _, p := range parameters {
+		t.Run(fmt.Sprintf("Testing %v", p.input), func(t *testing.T) {
+			_, err := ParseChordSymbol(p.input)
+			parseErr, ok := err.(*ParseError)
+			if !ok {
+				t.Fatalf("Expected a *ParseError, but got %v", err)
+			}
+			if parseErr.Offset != p.offset || parseErr.Token != p.token {
+				t.Errorf("Expected %q at offset %d, but got %q at offset %d", p.token, p.offset, parseErr.Token, parseErr.Offset)
+			}
+		})
+	}
+}
@@ -0,0 +1,225 @@
+package godio
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/samber/lo"
+)
+
+// noteLetters are the note letters in scale order along with the pitch class of their natural note
+var (
+	noteLetters       = "CDEFGAB"
+	naturalNoteNumber = []int{0, 2, 4, 5, 7, 9, 11}
+)
+
+// accidentalSymbols are the accidentals accepted by ParseNoteE and their value in semitones
+var accidentalSymbols = map[rune]int{
+	'#': 1,
+	'♯': 1,
+	'x': 2,
+	'𝄪': 2,
+	'b': -1,
+	'♭': -1,
+	'𝄫': -2,
+}
+
+// maxAccidental is the largest number of sharps or flats of a SpelledNote
+const maxAccidental = 2
+
+// intervalSteps is the number of letters spanned by the interval of each number
+// of semitones within an octave returned by Semitones. The tritone is spelled
+// as a diminished fifth.
+var intervalSteps = []int{0, 1, 1, 2, 2, 3, 4, 4, 5, 5, 6, 6}
+
+// PitchClass is a note regardless of its octave and spelling, from 0 for C to 11 for B
+type PitchClass int
+
+func (p PitchClass) String() string {
+	return sharpNoteNames[mod12(int(p))]
+}
+
+// Add returns the pitch class an interval above, or below for descending intervals
+func (p PitchClass) Add(interval Interval) PitchClass {
+	return PitchClass(mod12(int(p) + interval.Semitones))
+}
+
+// Pitch returns the pitch class in an octave, spelled with sharps
+func (p PitchClass) Pitch(octave int) Pitch {
+	return Pitch{Note: sharpNotes[mod12(int(p))], Octave: octave}
+}
+
+// SpelledNote is a note name made of a letter and accidentals, such as C, F#, Bb or Ebb.
+// The zero value stands for no note.
+type SpelledNote struct {
+	Letter     byte // Note letter from 'A' to 'G'
+	Accidental int  // Semitones added by the accidentals, from -2 for a double flat to 2 for a double sharp
+}
+
+// sharpNotes and flatNotes are the spellings of sharpNoteNames and flatNoteNames
+var (
+	sharpNotes = parseNotes(sharpNoteNames)
+	flatNotes  = parseNotes(flatNoteNames)
+)
+
+// ParseNote is like ParseNoteE but panics if the note name cannot be parsed
+func ParseNote(s string) SpelledNote {
+	note, err := ParseNoteE(s)
+	if err != nil {
+		panic(err)
+	}
+	return note
+}
+
+// ParseNoteE parses a note name made of a letter from A to G followed by up to
+// two sharps (#, ♯) or flats (b, ♭). Double sharps may also be written x or 𝄪
+// and double flats 𝄫.
+func ParseNoteE(s string) (SpelledNote, error) {
+	if s == "" || strings.IndexByte(noteLetters, s[0]) < 0 {
+		return SpelledNote{}, fmt.Errorf("invalid note %q: expected a note letter (A-G)", s)
+	}
+	note := SpelledNote{Letter: s[0]}
+	for _, char := range s[1:] {
+		semitones, ok := accidentalSymbols[char]
+		if !ok || semitones*note.Accidental < 0 {
+			return SpelledNote{}, fmt.Errorf("invalid note %q: unexpected %q, expected a sharp or a flat", s, char)
+		}
+		note.Accidental += semitones
+	}
+	if note.Accidental > maxAccidental || note.Accidental < -maxAccidental {
+		return SpelledNote{}, fmt.Errorf("invalid note %q: expected at most two sharps or flats", s)
+	}
+	return note, nil
+}
+
+// parseNotes parses note names that are known to be valid
+func parseNotes(names []string) []SpelledNote {
+	return lo.Map(names, func(name string, _ int) SpelledNote {
+		return ParseNote(name)
+	})
+}
+
+func (n SpelledNote) String() string {
+	if n.IsZero() {
+		return ""
+	}
+	if n.Accidental < 0 {
+		return string(n.Letter) + strings.Repeat("b", -n.Accidental)
+	}
+	return string(n.Letter) + strings.Repeat("#", n.Accidental)
+}
+
+// IsZero reports whether n is the zero SpelledNote, which stands for no note
+func (n SpelledNote) IsZero() bool {
+	return n.Letter == 0
+}
+
+// letterIndex returns the index of the letter of the note in noteLetters
+func (n SpelledNote) letterIndex() int {
+	return strings.IndexByte(noteLetters, n.Letter)
+}
+
+// PitchClass returns the pitch class of the note, so that both C# and Db are 1
+func (n SpelledNote) PitchClass() PitchClass {
+	return PitchClass(mod12(naturalNoteNumber[n.letterIndex()] + n.Accidental))
+}
+
+// Add returns the note an interval above, or below for descending intervals.
+// The letter moves by the size of the interval, as in Eb for a minor third
+// above C and F# for an augmented fourth, unless that would take more than two
+// accidentals.
+func (n SpelledNote) Add(interval Interval) SpelledNote {
+	return spellPitchClass(mod7(n.letterIndex()+interval.Steps), n.PitchClass().Add(interval))
+}
+
+// Pitch returns the note in an octave
+func (n SpelledNote) Pitch(octave int) Pitch {
+	return Pitch{Note: n, Octave: octave}
+}
+
+// spellPitchClass names a pitch class using the letter at index letter of
+// noteLetters, or with sharps when that would take more than two accidentals.
+func spellPitchClass(letter int, pitchClass PitchClass) SpelledNote {
+	accidental := mod12(int(pitchClass) - naturalNoteNumber[letter])
+	if accidental > 6 {
+		accidental -= 12
+	}
+	if accidental > maxAccidental || accidental < -maxAccidental {
+		return sharpNotes[pitchClass]
+	}
+	return SpelledNote{Letter: noteLetters[letter], Accidental: accidental}
+}
+
+// Pitch is a spelled note in an octave, such as A4 for the A at 440 Hz. The
+// octave follows the letter, so that B#3 sounds like C4.
+type Pitch struct {
+	Note   SpelledNote
+	Octave int // Scientific pitch notation octave, in which middle C is C4
+}
+
+// ParsePitch is like ParsePitchE but panics if the pitch cannot be parsed
+func ParsePitch(s string) Pitch {
+	pitch, err := ParsePitchE(s)
+	if err != nil {
+		panic(err)
+	}
+	return pitch
+}
+
+// ParsePitchE parses a note name followed by an octave, such as "A#3", "Eb4" or "C-1"
+func ParsePitchE(s string) (Pitch, error) {
+	octaveStart := strings.IndexAny(s, "-0123456789")
+	if octaveStart < 0 {
+		return Pitch{}, fmt.Errorf("invalid pitch %q: expected an octave", s)
+	}
+	note, err := ParseNoteE(s[:octaveStart])
+	if err != nil {
+		return Pitch{}, fmt.Errorf("invalid pitch %q: %w", s, err)
+	}
+	octave, err := strconv.Atoi(s[octaveStart:])
+	if err != nil {
+		return Pitch{}, fmt.Errorf("invalid pitch %q: expected an octave, got %q", s, s[octaveStart:])
+	}
+	return Pitch{Note: note, Octave: octave}, nil
+}
+
+// PitchFromMIDI returns the pitch of a MIDI note number spelled with sharps, so that 60 is C4
+func PitchFromMIDI(number int) Pitch {
+	return PitchClass(mod12(number)).Pitch(floorDiv(number, 12) - 1)
+}
+
+func (p Pitch) String() string {
+	return fmt.Sprintf("%s%d", p.Note, p.Octave)
+}
+
+// MIDI returns the MIDI note number of the pitch, 60 for C4 and 69 for A4
+func (p Pitch) MIDI() int {
+	return (p.Octave+1)*12 + naturalNoteNumber[p.Note.letterIndex()] + p.Note.Accidental
+}
+
+// Add returns the pitch an interval above, or below for descending intervals, spelled as SpelledNote.Add
+func (p Pitch) Add(interval Interval) Pitch {
+	note := p.Note.Add(interval)
+	natural := naturalNoteNumber[note.letterIndex()] + note.Accidental
+	return Pitch{Note: note, Octave: floorDiv(p.MIDI()+interval.Semitones-natural, 12) - 1}
+}
+
+// Frequency returns the frequency of the pitch in Hz in StandardTuning
+func (p Pitch) Frequency() float64 {
+	return StandardTuning.Frequency(p.MIDI())
+}
+
+// floorDiv divides rounding towards negative infinity
+func floorDiv(a int, b int) int {
+	if a%b != 0 && (a < 0) != (b < 0) {
+		return a/b - 1
+	}
+	return a / b
+}
+
+// isNoteName reports whether s is a note name accepted by ParseNoteE
+func isNoteName(s string) bool {
+	_, err := ParseNoteE(s)
+	return err == nil
+}
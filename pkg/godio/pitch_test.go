@@ -0,0 +1,122 @@
+package godio
+
+import (
+	"fmt"
+	"math"
+	"testing"
+)
+
+func TestParseNote(t *testing.T) {
+	parameters := []struct {
+		input      string
+		expected   string
+		pitchClass PitchClass
+	}{
+		{"C", "C", 0},
+		{"F#", "F#", 6},
+		{"Bb", "Bb", 10},
+		{"B♭", "Bb", 10},
+		{"Cb", "Cb", 11},
+		{"E#", "E#", 5},
+		{"Fx", "F##", 7},
+		{"G##", "G##", 9},
+		{"Ebb", "Ebb", 2},
+		{"D𝄫", "Dbb", 0},
+	}
+
+	for _, p := range parameters {
+		t.Run(fmt.Sprintf("Testing %v", p.input), func(t *testing.T) {
+			note, err := ParseNoteE(p.input)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if note.String() != p.expected || note.PitchClass() != p.pitchClass {
+				t.Errorf("Expected %v (%d), but got %v (%d)", p.expected, p.pitchClass, note, note.PitchClass())
+			}
+		})
+	}
+
+	for _, input := range []string{"", "H", "C#b", "Cbbb", "c", "C7"} {
+		if _, err := ParseNoteE(input); err == nil {
+			t.Errorf("Expected an error for %q", input)
+		}
+	}
+}
+
+func TestSpelledNoteAdd(t *testing.T) {
+	parameters := []struct {
+		note     string
+		interval Interval
+		expected string
+	}{
+		{"C", MinorThird, "Eb"},
+		{"C", MajorThird, "E"},
+		{"F#", MajorThird, "A#"},
+		{"Bb", PerfectFifth, "F"},
+		{"E", MajorSeventh, "D#"},
+		{"C#", MajorThird, "E#"},
+		{"Gb", MinorThird, "Bbb"},
+		{"D", MajorSecond.Descending(), "C"},
+		{"C", AugmentedFourth, "F#"},
+		{"C", DiminishedFifth, "Gb"},
+		{"E", AugmentedSecond, "F##"},
+		{"A", MajorNinth, "B"},
+	}
+
+	for _, p := range parameters {
+		t.Run(fmt.Sprintf("Testing %v+%v", p.note, p.interval), func(t *testing.T) {
+			note := ParseNote(p.note).Add(p.interval)
+			if note.String() != p.expected {
+				t.Errorf("Expected %v, but got %v", p.expected, note)
+			}
+		})
+	}
+}
+
+func TestPitch(t *testing.T) {
+	parameters := []struct {
+		input string
+		midi  int
+	}{
+		{"C4", 60},
+		{"A4", 69},
+		{"A#3", 58},
+		{"Cb4", 59},
+		{"B#3", 60},
+		{"C-1", 0},
+		{"G9", 127},
+	}
+
+	for _, p := range parameters {
+		t.Run(fmt.Sprintf("Testing %v", p.input), func(t *testing.T) {
+			pitch, err := ParsePitchE(p.input)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if pitch.MIDI() != p.midi {
+				t.Errorf("Expected MIDI note %d, but got %d", p.midi, pitch.MIDI())
+			}
+			if pitch.String() != p.input {
+				t.Errorf("Expected %v, but got %v", p.input, pitch)
+			}
+			if PitchFromMIDI(p.midi).MIDI() != p.midi {
+				t.Errorf("Expected %v to round trip through MIDI, but got %v", p.input, PitchFromMIDI(p.midi))
+			}
+		})
+	}
+
+	if pitch := ParsePitch("B3").Add(MinorSecond); pitch.String() != "C4" {
+		t.Errorf("Expected C4, but got %v", pitch)
+	}
+	if pitch := ParsePitch("C4").Add(MinorThird.Descending()); pitch.String() != "A3" {
+		t.Errorf("Expected A3, but got %v", pitch)
+	}
+	if frequency := ParsePitch("A3").Frequency(); math.Abs(frequency-220) > 1e-9 {
+		t.Errorf("Expected 220 Hz, but got %v", frequency)
+	}
+	for _, input := range []string{"C", "H4", "C#x"} {
+		if _, err := ParsePitchE(input); err == nil {
+			t.Errorf("Expected an error for %q", input)
+		}
+	}
+}
@@ -0,0 +1,169 @@
+package godio
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+
+	"github.com/samber/lo"
+)
+
+// ChordRegistry holds the chord qualities understood by ParseChordE, each with
+// the intervals above the root of its tones, and the other spellings of them.
+// Qualities are written as in chord symbols, such as "m7", "7sus4b9" or
+// "dim(maj7)", and are looked up the way the parser reads them, so that
+// "-7" and "mi7" both find "m7".
+type ChordRegistry struct {
+	formulas map[string][]Interval
+	aliases  map[string]string
+}
+
+// DefaultChordRegistry is the registry used by ParseChordE and by every chord
+// of the package. Teams with their own chord vocabulary can register qualities
+// on it, or replace it with a registry of their own.
+var DefaultChordRegistry = NewChordRegistry()
+
+// NewChordRegistry returns a registry of the built-in qualities and aliases
+func NewChordRegistry() *ChordRegistry {
+	r := &ChordRegistry{formulas: map[string][]Interval{}, aliases: map[string]string{}}
+	for quality, formula := range chordFormulas {
+		r.formulas[quality] = slices.Clone(formula)
+	}
+	for alias, quality := range qualityAliases {
+		r.aliases[alias] = quality
+	}
+	return r
+}
+
+// Register makes a quality available with the intervals of its tones above the
+// root, replacing any formula it had. As in the built-in formulas, the root is
+// implied and extensions are best written within the octave of the root.
+func (r *ChordRegistry) Register(quality string, formula []Interval) {
+	r.formulas[normalizeQuality(quality)] = slices.Clone(formula)
+}
+
+// Alias makes another spelling stand for a registered quality, as "maj7#5"
+// would for "augmaj7"
+func (r *ChordRegistry) Alias(alias string, quality string) error {
+	key, ok := r.lookup(normalizeQuality(quality))
+	if !ok {
+		return fmt.Errorf("cannot alias %q to unknown chord quality %q", alias, quality)
+	}
+	r.aliases[normalizeQuality(alias)] = key
+	return nil
+}
+
+// Formula returns the intervals above the root of the tones of a quality or of
+// one of its aliases, and whether the quality is known
+func (r *ChordRegistry) Formula(quality string) ([]Interval, bool) {
+	key, ok := r.lookup(normalizeQuality(quality))
+	if !ok {
+		return nil, false
+	}
+	return slices.Clone(r.formulas[key]), true
+}
+
+// Qualities returns the registered qualities in alphabetical order, without aliases
+func (r *ChordRegistry) Qualities() []string {
+	qualities := lo.Keys(r.formulas)
+	slices.Sort(qualities)
+	return qualities
+}
+
+// chordRegistryJSON is the JSON representation of the definitions read by
+// ChordRegistry.Load. Intervals are written as read by ParseIntervalE.
+type chordRegistryJSON struct {
+	Qualities map[string][]string `json:"qualities"`
+	Aliases   map[string]string   `json:"aliases"`
+}
+
+// Load registers the qualities and aliases of a JSON document such as
+//
+//	{
+//	  "qualities": {"7sus4b9": ["P4", "P5", "m7", "b9"]},
+//	  "aliases": {"7susb9": "7sus4b9"}
+//	}
+//
+// where intervals are written with their quality, as "m7", or as a scale degree,
+// as "b9". Nothing is registered when the document is invalid.
+func (r *ChordRegistry) Load(reader io.Reader) error {
+	var data chordRegistryJSON
+	if err := json.NewDecoder(reader).Decode(&data); err != nil {
+		return fmt.Errorf("invalid chord definitions: %w", err)
+	}
+
+	formulas := map[string][]Interval{}
+	for quality, intervals := range data.Qualities {
+		formula := make([]Interval, len(intervals))
+		for i, text := range intervals {
+			interval, err := ParseIntervalE(text)
+			if err != nil {
+				return fmt.Errorf("invalid chord quality %q: %w", quality, err)
+			}
+			formula[i] = interval
+		}
+		formulas[quality] = formula
+	}
+
+	// Aliases are checked against a copy so that a failed load leaves the registry unchanged
+	loaded := r.clone()
+	for quality, formula := range formulas {
+		loaded.Register(quality, formula)
+	}
+	for alias, quality := range data.Aliases {
+		if err := loaded.Alias(alias, quality); err != nil {
+			return fmt.Errorf("invalid chord definitions: %w", err)
+		}
+	}
+	r.formulas, r.aliases = loaded.formulas, loaded.aliases
+	return nil
+}
+
+func (r *ChordRegistry) clone() *ChordRegistry {
+	clone := &ChordRegistry{formulas: map[string][]Interval{}, aliases: map[string]string{}}
+	for quality, formula := range r.formulas {
+		clone.formulas[quality] = formula
+	}
+	for alias, quality := range r.aliases {
+		clone.aliases[alias] = quality
+	}
+	return clone
+}
+
+// lookup returns the registered quality spelled by text, normalized as by parseQuality
+func (r *ChordRegistry) lookup(text string) (string, bool) {
+	if key, ok := r.aliases[text]; ok {
+		return key, true
+	}
+	if _, ok := r.formulas[text]; ok {
+		return text, true
+	}
+	// A lone Δ is a major seventh chord, otherwise it stands for "maj" as in Δ9
+	text = strings.ReplaceAll(text, "Δ", "maj")
+	if key, ok := r.aliases[text]; ok {
+		return key, true
+	}
+	_, ok := r.formulas[text]
+	return text, ok
+}
+
+// formula returns the formula of a registered quality, nil when it is unknown
+func (r *ChordRegistry) formula(quality string) []Interval {
+	return r.formulas[quality]
+}
+
+// normalizeQuality spells a quality as parseQuality reads it from the tokens of
+// a chord symbol, with the symbols and abbreviations of qualitySymbols resolved
+func normalizeQuality(quality string) string {
+	text := ""
+	for _, tok := range lexChord(quality) {
+		if symbol, ok := qualitySymbols[tok.text]; ok {
+			text += symbol
+		} else {
+			text += tok.text
+		}
+	}
+	return text
+}
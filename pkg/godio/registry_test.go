@@ -0,0 +1,106 @@
+package godio
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestChordRegistryBuiltins(t *testing.T) {
+	parameters := []struct {
+		symbol  string
+		quality string
+		tones   []int
+	}{
+		{"C5", "5", []int{0, 7}},
+		{"Cmaj7#5", "augmaj7", []int{0, 4, 8, 11}},
+		{"C7sus4b9", "7sus4b9", []int{0, 5, 7, 10, 1}},
+		{"Cdim(maj7)", "dim(maj7)", []int{0, 3, 6, 11}},
+		{"Co(maj7)", "dim(maj7)", []int{0, 3, 6, 11}},
+		{"C^", "maj7", []int{0, 4, 7, 11}},
+	}
+
+	for _, p := range parameters {
+		t.Run(fmt.Sprintf("Testing %v", p.symbol), func(t *testing.T) {
+			chord := MustParseChord(p.symbol)
+			if chord.Quality != p.quality {
+				t.Errorf("Expected the quality %q, but got %q", p.quality, chord.Quality)
+			}
+			if !slices.Equal(chord.Tones, p.tones) {
+				t.Errorf("Expected %v, but got %v", p.tones, chord.Tones)
+			}
+		})
+	}
+}
+
+func TestChordRegistryRegister(t *testing.T) {
+	defer func(registry *ChordRegistry) { DefaultChordRegistry = registry }(DefaultChordRegistry)
+	DefaultChordRegistry = NewChordRegistry()
+
+	if _, err := ParseChordE("Cquartal"); err == nil {
+		t.Fatalf("Expected an error before registering the quality, but got nil")
+	}
+	DefaultChordRegistry.Register("quartal", []Interval{PerfectFourth, MinorSeventh})
+	if err := DefaultChordRegistry.Alias("q", "quartal"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	for _, symbol := range []string{"Cquartal", "Cq"} {
+		chord, err := ParseChordE(symbol)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if chord.Quality != "quartal" || !slices.Equal(chord.Tones, []int{0, 5, 10}) {
+			t.Errorf("Expected quartal [0 5 10] for %v, but got %v %v", symbol, chord.Quality, chord.Tones)
+		}
+	}
+
+	if err := DefaultChordRegistry.Alias("x", "unknown"); err == nil {
+		t.Errorf("Expected an error for an alias of an unknown quality, but got nil")
+	}
+	if NewChordRegistry().Qualities()[0] != "" || slices.Contains(NewChordRegistry().Qualities(), "quartal") {
+		t.Errorf("Expected new registries to only hold the built-in qualities")
+	}
+}
+
+func TestChordRegistryLoad(t *testing.T) {
+	registry := NewChordRegistry()
+	err := registry.Load(strings.NewReader(`{
+		"qualities": {"mi11": ["m3", "P5", "m7", "9", "11"]},
+		"aliases": {"-11": "mi11", "7susb9": "7sus4b9"}
+	}`))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	parameters := []struct {
+		quality  string
+		expected []Interval
+	}{
+		{"m11", []Interval{MinorThird, PerfectFifth, MinorSeventh, MajorNinth, PerfectEleventh}},
+		{"-11", []Interval{MinorThird, PerfectFifth, MinorSeventh, MajorNinth, PerfectEleventh}},
+		{"7susb9", []Interval{PerfectFourth, PerfectFifth, MinorSeventh, MinorSecond}},
+	}
+	for _, p := range parameters {
+		t.Run(fmt.Sprintf("Testing %v", p.quality), func(t *testing.T) {
+			formula, ok := registry.Formula(p.quality)
+			if !ok || !slices.Equal(formula, p.expected) {
+				t.Errorf("Expected %v, but got %v (%v)", p.expected, formula, ok)
+			}
+		})
+	}
+
+	for _, data := range []string{
+		`{"qualities": {"x": ["M3", "Q5"]}}`,
+		`{"aliases": {"x": "unknown"}}`,
+		`{"qualities": [`,
+	} {
+		before := registry.Qualities()
+		if err := registry.Load(strings.NewReader(data)); err == nil {
+			t.Errorf("Expected an error for %v, but got nil", data)
+		}
+		if !slices.Equal(registry.Qualities(), before) {
+			t.Errorf("Expected a failed load to leave the registry unchanged")
+		}
+	}
+}
@@ -0,0 +1,221 @@
+// Package reharm rewrites godio chord progressions with jazz reharmonization
+// techniques such as tritone substitutions and Coltrane changes.
+package reharm
+
+import (
+	"fmt"
+	"math/rand"
+	"slices"
+	"strings"
+
+	"github.com/kimond/godio/pkg/godio"
+	"github.com/samber/lo"
+)
+
+// Transform is a reharmonization technique. Rewrite returns the chords replacing
+// the chord at an index of a progression, or nil when the technique does not
+// apply there.
+type Transform struct {
+	Name        string
+	Description string
+	Rewrite     func(p godio.Progression, i int) []*godio.Chord
+}
+
+var (
+	Coltrane = Transform{
+		Name:        "coltrane",
+		Description: "Replace a dominant resolving to a major chord with Coltrane changes, as in Eb7 Abmaj7 B7 Emaj7 G7 for G7 before C",
+		Rewrite:     coltrane,
+	}
+	TritoneSubstitution = Transform{
+		Name:        "tritone",
+		Description: "Replace a dominant resolving down a fifth with the dominant a tritone away, as in Db7 for G7 before C",
+		Rewrite:     tritoneSubstitution,
+	}
+	Backdoor = Transform{
+		Name:        "backdoor",
+		Description: "Replace a dominant resolving to a major chord with the backdoor dominant a whole step below the major chord, as in Bb7 for G7 before C",
+		Rewrite:     backdoor,
+	}
+	RelatedII = Transform{
+		Name:        "ii",
+		Description: "Insert the related ii chord before a dominant, as in Dm7 before G7",
+		Rewrite:     relatedII,
+	}
+	PassingDiminished = Transform{
+		Name:        "passing",
+		Description: "Insert a diminished seventh chord between chords a whole step apart, as in C#dim7 between C and Dm",
+		Rewrite:     passingDiminished,
+	}
+	Upgrade = Transform{
+		Name:        "upgrade",
+		Description: "Turn triads into seventh chords and seventh chords into ninth chords",
+		Rewrite:     upgrade,
+	}
+)
+
+// Transforms are the known transforms, in the order a Reharmonizer applies them.
+// Substitutions of dominants come first so that the ii and passing chords are
+// inserted around the substitutes, and chord qualities are upgraded last.
+var Transforms = []Transform{Coltrane, TritoneSubstitution, Backdoor, RelatedII, PassingDiminished, Upgrade}
+
+// TransformByName returns the transform of Transforms with a name
+func TransformByName(name string) (Transform, error) {
+	transform, ok := lo.Find(Transforms, func(transform Transform) bool {
+		return transform.Name == strings.ToLower(name)
+	})
+	if !ok {
+		names := lo.Map(Transforms, func(transform Transform, _ int) string { return transform.Name })
+		slices.Sort(names)
+		return Transform{}, fmt.Errorf("unknown transform %q, expected one of %s", name, strings.Join(names, ", "))
+	}
+	return transform, nil
+}
+
+// Reharmonizer rewrites progressions with transforms chosen at random. The same
+// seed always gives the same rewrites.
+type Reharmonizer struct {
+	Transforms  []Transform
+	Probability float64 // Chance of applying a transform at each chord it applies to
+	Seed        int64
+}
+
+// NewReharmonizer returns a Reharmonizer applying every one of Transforms half
+// of the time
+func NewReharmonizer(seed int64) *Reharmonizer {
+	return &Reharmonizer{
+		Transforms:  slices.Clone(Transforms),
+		Probability: 0.5,
+		Seed:        seed,
+	}
+}
+
+// Reharmonize returns the progression rewritten by each transform in turn, the
+// chords of the original progression being kept or replaced but never changed
+func (r *Reharmonizer) Reharmonize(p godio.Progression) godio.Progression {
+	rng := rand.New(rand.NewSource(r.Seed))
+	reharmonized := slices.Clone(p)
+	for _, transform := range r.Transforms {
+		rewritten := godio.Progression{}
+		for i := range reharmonized {
+			// Draw for every chord so that a transform does not shift the draws of the next ones
+			apply := rng.Float64() < r.Probability
+			if chords := transform.Rewrite(reharmonized, i); chords != nil && apply {
+				rewritten = append(rewritten, chords...)
+				continue
+			}
+			rewritten = append(rewritten, reharmonized[i])
+		}
+		reharmonized = rewritten
+	}
+	return reharmonized
+}
+
+func coltrane(p godio.Progression, i int) []*godio.Chord {
+	if !isDominant(p[i]) || !resolvesDownAFifth(p, i) || !hasTone(p[i+1], 4) {
+		return nil
+	}
+	target := p[i+1].Root.PitchClass()
+	return []*godio.Chord{
+		chord(godio.FlatSpelling.Spell(target.Add(godio.MinorThird)), "7"),
+		chord(godio.FlatSpelling.Spell(target.Add(godio.MinorSixth)), "maj7"),
+		chord(godio.FlatSpelling.Spell(target.Add(godio.MajorSeventh)), "7"),
+		chord(godio.FlatSpelling.Spell(target.Add(godio.MajorThird)), "maj7"),
+		p[i],
+	}
+}
+
+func tritoneSubstitution(p godio.Progression, i int) []*godio.Chord {
+	if !isDominant(p[i]) || !resolvesDownAFifth(p, i) {
+		return nil
+	}
+	return []*godio.Chord{chord(godio.FlatSpelling.Spell(p[i+1].Root.PitchClass().Add(godio.MinorSecond)), "7")}
+}
+
+func backdoor(p godio.Progression, i int) []*godio.Chord {
+	if !isDominant(p[i]) || !resolvesDownAFifth(p, i) || !hasTone(p[i+1], 4) {
+		return nil
+	}
+	return []*godio.Chord{chord(godio.FlatSpelling.Spell(p[i+1].Root.PitchClass().Add(godio.MinorSeventh)), "7")}
+}
+
+// relatedII inserts a minor seventh chord a fifth above a dominant, half-diminished
+// when the dominant resolves to a minor chord, unless the dominant already follows it
+func relatedII(p godio.Progression, i int) []*godio.Chord {
+	if !isDominant(p[i]) {
+		return nil
+	}
+	ii := p[i].Root.Add(godio.PerfectFifth)
+	if i > 0 && p[i-1].Root.PitchClass() == ii.PitchClass() && hasTone(p[i-1], 3) {
+		return nil
+	}
+	quality := "m7"
+	if resolvesDownAFifth(p, i) && hasTone(p[i+1], 3) {
+		quality = "ø7"
+	}
+	return []*godio.Chord{chord(ii, quality), p[i]}
+}
+
+func passingDiminished(p godio.Progression, i int) []*godio.Chord {
+	if i+1 >= len(p) || interval(p[i].Root, p[i+1].Root) != 2 {
+		return nil
+	}
+	return []*godio.Chord{p[i], chord(godio.SharpSpelling.Spell(p[i].Root.PitchClass().Add(godio.MinorSecond)), "dim7")}
+}
+
+// upgrades are the qualities that Upgrade turns chords into. Major triads become
+// dominant seventh chords when they resolve down a fifth.
+var upgrades = map[string]string{
+	"":     "maj7",
+	"maj":  "maj7",
+	"m":    "m7",
+	"dim":  "ø7",
+	"7":    "9",
+	"m7":   "m9",
+	"maj7": "maj9",
+}
+
+// upgrade rewrites root position chords without extensions, omissions or upper
+// structure, as the upgraded quality would bring back the omitted tones
+func upgrade(p godio.Progression, i int) []*godio.Chord {
+	c := p[i]
+	quality, ok := upgrades[c.Quality]
+	slash := !c.BassNote.IsZero() && c.BassNote != c.Root
+	if !ok || len(c.Extensions) > 0 || len(c.Omissions) > 0 || slash || c.Upper != nil {
+		return nil
+	}
+	if quality == "maj7" && resolvesDownAFifth(p, i) {
+		quality = "7"
+	}
+	return []*godio.Chord{chord(c.Root, quality)}
+}
+
+// chord returns the chord of a quality of chordFormulas on a root
+func chord(root godio.SpelledNote, quality string) *godio.Chord {
+	return godio.MustParseChord(root.String() + quality)
+}
+
+// isDominant reports whether a chord has a major third and a minor seventh
+func isDominant(c *godio.Chord) bool {
+	return hasTone(c, 4) && hasTone(c, 10)
+}
+
+// hasTone reports whether a chord has a tone a number of semitones above its root in any octave
+func hasTone(c *godio.Chord, semitones int) bool {
+	return slices.ContainsFunc(c.Tones, func(tone int) bool { return mod12(tone) == semitones })
+}
+
+// resolvesDownAFifth reports whether the chord at an index is followed by a chord a fifth below
+func resolvesDownAFifth(p godio.Progression, i int) bool {
+	return i+1 < len(p) && interval(p[i].Root, p[i+1].Root) == 5
+}
+
+// interval returns the number of semitones from a note up to another, from 0 to 11
+func interval(from godio.SpelledNote, to godio.SpelledNote) int {
+	return mod12(int(to.PitchClass()) - int(from.PitchClass()))
+}
+
+// mod12 returns n modulo 12 from 0 to 11, even for negative numbers
+func mod12(n int) int {
+	return ((n % 12) + 12) % 12
+}
@@ -0,0 +1,81 @@
+package reharm
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/kimond/godio/pkg/godio"
+	"github.com/samber/lo"
+)
+
+func parseProgression(symbols string) godio.Progression {
+	return lo.Map(strings.Fields(symbols), func(symbol string, _ int) *godio.Chord {
+		return godio.MustParseChord(symbol)
+	})
+}
+
+func TestTransforms(t *testing.T) {
+	parameters := []struct {
+		transform   Transform
+		progression string
+		expected    string
+	}{
+		{Coltrane, "Dm7 G7 Cmaj7", "Dm7 Eb7 Abmaj7 B7 Emaj7 G7 Cmaj7"},
+		{Coltrane, "Ebm7 Ab7 Dbmaj7", "Ebm7 E7 Amaj7 C7 Fmaj7 Ab7 Dbmaj7"},
+		{Coltrane, "Abm7 Db7 Gbmaj7", "Abm7 A7 Dmaj7 F7 Bbmaj7 Db7 Gbmaj7"},
+		{Coltrane, "Dm7 G7 Cm7", "Dm7 G7 Cm7"},
+		{TritoneSubstitution, "Dm7 G7 Cmaj7", "Dm7 Db7 Cmaj7"},
+		{TritoneSubstitution, "Cm7 F7 Bbmaj7", "Cm7 B7 Bbmaj7"},
+		{TritoneSubstitution, "G7 Dm7", "G7 Dm7"},
+		{Backdoor, "Fm7 G7 C", "Fm7 Bb7 C"},
+		{RelatedII, "C A7 Dm7 G7 C", "C Eø7 A7 Dm7 G7 C"},
+		{RelatedII, "E7 Am", "Bø7 E7 Am"},
+		{PassingDiminished, "C Dm7 G7", "C C#dim7 Dm7 G7"},
+		{PassingDiminished, "Eb F7 Bb", "Eb Edim7 F7 Bb"},
+		{Upgrade, "C Am Dm G C", "Cmaj7 Am7 Dm7 G7 Cmaj7"},
+		{Upgrade, "Dm7 G7 Cmaj7 Bdim C/E", "Dm9 G9 Cmaj9 Bø7 C/E"},
+		{Upgrade, "C(no3) Am C7(omit5)", "C(no3) Am7 C7(no5)"},
+	}
+
+	for _, p := range parameters {
+		t.Run(fmt.Sprintf("Testing %v on %v", p.transform.Name, p.progression), func(t *testing.T) {
+			reharmonizer := &Reharmonizer{Transforms: []Transform{p.transform}, Probability: 1}
+			reharmonized := reharmonizer.Reharmonize(parseProgression(p.progression))
+			if reharmonized.String() != p.expected {
+				t.Errorf("Expected %v, but got %v", p.expected, reharmonized)
+			}
+		})
+	}
+}
+
+func TestReharmonizeSeed(t *testing.T) {
+	progression := parseProgression("Cmaj7 A7 Dm7 G7 Cmaj7 E7 Am7 D7 Dm7 G7 C")
+	first := NewReharmonizer(42).Reharmonize(progression)
+	if second := NewReharmonizer(42).Reharmonize(progression); second.String() != first.String() {
+		t.Errorf("Expected %v with the same seed, but got %v", first, second)
+	}
+
+	outcomes := lo.Uniq(lo.Map(lo.Range(10), func(seed int, _ int) string {
+		return NewReharmonizer(int64(seed)).Reharmonize(progression).String()
+	}))
+	if len(outcomes) < 2 {
+		t.Errorf("Expected different seeds to give different progressions, but got %v", outcomes)
+	}
+
+	none := &Reharmonizer{Probability: 1}
+	if unchanged := none.Reharmonize(progression); unchanged.String() != progression.String() {
+		t.Errorf("Expected %v without transforms, but got %v", progression, unchanged)
+	}
+}
+
+func TestTransformByName(t *testing.T) {
+	for _, transform := range Transforms {
+		if found, err := TransformByName(strings.ToUpper(transform.Name)); err != nil || found.Name != transform.Name {
+			t.Errorf("Expected %v, but got %v (%v)", transform.Name, found.Name, err)
+		}
+	}
+	if _, err := TransformByName("reverse"); err == nil {
+		t.Errorf("Expected an error for an unknown transform, but got nil")
+	}
+}
@@ -0,0 +1,241 @@
+package godio
+
+import (
+	"errors"
+	"slices"
+	"strings"
+)
+
+// romanNumerals are the Roman numerals of the scale degrees, longest first
+var romanNumerals = []struct {
+	numeral string
+	degree  int
+}{
+	{"VII", 7}, {"III", 3}, {"VI", 6}, {"IV", 4}, {"II", 2}, {"V", 5}, {"I", 1},
+}
+
+// romanQualities maps the symbols following a Roman numeral to the triad quality they stand for
+var romanQualities = map[string]string{
+	"°": "dim",
+	"o": "dim",
+	"ø": "ø7",
+	"+": "aug",
+}
+
+// inversionFigures maps figured bass inversion symbols to whether they denote a
+// seventh chord and which chord tone is in the bass, 0 for the root to 3 for the seventh.
+var inversionFigures = map[string]struct {
+	seventh   bool
+	inversion int
+}{
+	"":   {false, 0},
+	"6":  {false, 1},
+	"64": {false, 2},
+	"7":  {true, 0},
+	"65": {true, 1},
+	"43": {true, 2},
+	"42": {true, 3},
+	"2":  {true, 3},
+}
+
+// ParseChord parses a chord relative to the key, written as a Roman numeral, a
+// Nashville number or an absolute chord symbol.
+func (k Key) ParseChord(symbol string) (*Chord, error) {
+	_, offset := parseAccidentals(symbol)
+	if numeral, _ := matchRomanNumeral(symbol[offset:]); numeral != "" {
+		return ParseRomanNumeral(k, symbol)
+	}
+	if offset < len(symbol) && symbol[offset] >= '0' && symbol[offset] <= '9' {
+		return ParseNashville(k, symbol)
+	}
+	return ParseChordE(symbol)
+}
+
+// ParseRomanNumeral parses a Roman numeral chord relative to a key, such as "ii7",
+// "V65", "bVII", "vii°7" or the secondary dominant "V7/ii". Uppercase numerals are
+// major and lowercase numerals minor unless followed by °, ø or +. A trailing
+// figure (6, 64, 7, 65, 43, 42) gives the inversion, any other suffix is read as
+// the rest of a chord symbol, as in "Imaj7" or "V7b9". Degrees of minor keys
+// follow the natural minor scale, except that lowercase numerals on the seventh
+// degree are built on the raised leading tone as in harmonic minor, and flats
+// on the third, sixth and seventh degrees name the notes of the parallel major
+// key lowered, as in bVI.
+func ParseRomanNumeral(key Key, symbol string) (*Chord, error) {
+	// A secondary chord is built in the key of its target degree
+	main, _, secondary := strings.Cut(symbol, "/")
+	if secondary {
+		targetKey, err := romanKey(key, symbol, len(main)+1)
+		if err != nil {
+			return nil, err
+		}
+		key = targetKey
+	}
+
+	accidental, offset := parseAccidentals(main)
+	numeral, degree := matchRomanNumeral(main[offset:])
+	if numeral == "" {
+		return nil, &ParseError{Input: symbol, Offset: offset, Token: tokenAt(symbol, offset), Expected: "a Roman numeral (I-VII)"}
+	}
+	offset += len(numeral)
+	minor := numeral == strings.ToLower(numeral)
+	if key.Mode == ModeMinor && degree == 7 && minor && accidental == 0 {
+		// The leading-tone chord of minor keys is built on the raised seventh of harmonic minor
+		accidental = 1
+	}
+
+	quality := ""
+	if minor {
+		quality = "m"
+	}
+	for glyph, glyphQuality := range romanQualities {
+		if strings.HasPrefix(main[offset:], glyph) {
+			quality = glyphQuality
+			offset += len(glyph)
+			break
+		}
+	}
+
+	suffix := main[offset:]
+	figure, isFigure := inversionFigures[suffix]
+	if !isFigure {
+		// Anything else completes the chord symbol, e.g. "maj7" or "7b9"
+		if quality == "m" && strings.HasPrefix(suffix, "m") && !strings.HasPrefix(suffix, "maj") {
+			quality = ""
+		}
+		return parseRelativeChord(symbol, key.degreeNote(degree, accidental).String(), quality, suffix, offset, "")
+	}
+	if figure.seventh {
+		switch quality {
+		case "":
+			quality = "7"
+		case "dim":
+			quality = "dim7"
+		case "aug":
+			quality = "aug7"
+		case "m":
+			quality = "m7"
+		}
+	}
+	root := key.degreeNote(degree, accidental)
+	bass := ""
+	if figure.inversion > 0 {
+		bass = chordToneNote(root, quality, figure.inversion).String()
+	}
+	return parseRelativeChord(symbol, root.String(), quality, "", offset, bass)
+}
+
+// ParseNashville parses a Nashville number chord relative to a key, such as "1",
+// "2m7", "b7", "5sus" or "1/3" where the bass note is also a scale degree.
+// The degree is followed by the rest of a chord symbol, and chords are major
+// unless a quality is given.
+func ParseNashville(key Key, symbol string) (*Chord, error) {
+	main, bassDegree, hasBass := strings.Cut(symbol, "/")
+
+	accidental, offset := parseAccidentals(main)
+	if offset >= len(main) || main[offset] < '1' || main[offset] > '7' {
+		return nil, &ParseError{Input: symbol, Offset: offset, Token: tokenAt(symbol, offset), Expected: "a scale degree (1-7)"}
+	}
+	root := key.degreeNote(int(main[offset]-'0'), accidental).String()
+
+	bass := ""
+	if hasBass {
+		bassOffset := len(main) + 1
+		bassAccidental, length := parseAccidentals(bassDegree)
+		if length != len(bassDegree)-1 || bassDegree[length] < '1' || bassDegree[length] > '7' {
+			return nil, &ParseError{Input: symbol, Offset: bassOffset + length, Token: tokenAt(symbol, bassOffset+length), Expected: "a bass scale degree (1-7)"}
+		}
+		bass = key.degreeNote(int(bassDegree[length]-'0'), bassAccidental).String()
+	}
+	return parseRelativeChord(symbol, root, "", main[offset+1:], offset+1, bass)
+}
+
+// romanKey returns the key of the target of a secondary chord, such as D minor for
+// the "ii" of "V7/ii" in C major. The target starts at offset in symbol and may
+// itself be a secondary chord, as in "V/V/V".
+func romanKey(key Key, symbol string, offset int) (Key, error) {
+	target, _, secondary := strings.Cut(symbol[offset:], "/")
+	if secondary {
+		targetKey, err := romanKey(key, symbol, offset+len(target)+1)
+		if err != nil {
+			return Key{}, err
+		}
+		key = targetKey
+	}
+
+	accidental, length := parseAccidentals(target)
+	numeral, degree := matchRomanNumeral(target[length:])
+	if numeral == "" {
+		return Key{}, &ParseError{Input: symbol, Offset: offset + length, Token: tokenAt(symbol, offset+length), Expected: "a Roman numeral (I-VII)"}
+	}
+	mode := ModeMajor
+	if numeral == strings.ToLower(numeral) || strings.ContainsAny(target[length+len(numeral):], "°oø") {
+		mode = ModeMinor
+	}
+	return Key{Tonic: key.degreeNote(degree, accidental), Mode: mode}, nil
+}
+
+// parseAccidentals parses the leading flats and sharps of s and returns their
+// value in semitones along with their length in bytes.
+func parseAccidentals(s string) (int, int) {
+	semitones := 0
+	for offset, char := range s {
+		switch char {
+		case 'b', '♭':
+			semitones--
+		case '#', '♯':
+			semitones++
+		default:
+			return semitones, offset
+		}
+	}
+	return semitones, len(s)
+}
+
+// matchRomanNumeral returns the Roman numeral at the start of s and its degree.
+// The numeral must be all uppercase or all lowercase.
+func matchRomanNumeral(s string) (string, int) {
+	for _, roman := range romanNumerals {
+		for _, numeral := range []string{roman.numeral, strings.ToLower(roman.numeral)} {
+			if strings.HasPrefix(s, numeral) {
+				return numeral, roman.degree
+			}
+		}
+	}
+	return "", 0
+}
+
+// chordToneNote returns the third, fifth or seventh of a chord, for inversions 1 to 3
+func chordToneNote(root SpelledNote, quality string, inversion int) SpelledNote {
+	tones := map[int][]int{1: {3, 4}, 2: {6, 7, 8}, 3: {9, 10, 11}}[inversion]
+	for _, tone := range DefaultChordRegistry.formula(quality) {
+		if slices.Contains(tones, tone.Semitones) {
+			letter := (root.letterIndex() + 2*inversion) % 7
+			return spellPitchClass(letter, root.PitchClass().Add(tone))
+		}
+	}
+	return SpelledNote{}
+}
+
+// parseRelativeChord parses the chord symbol made of root, quality, suffix and an
+// optional bass. Errors in suffix, which starts at offset in symbol, are reported
+// relative to symbol.
+func parseRelativeChord(symbol string, root string, quality string, suffix string, offset int, bass string) (*Chord, error) {
+	prefix := root + quality
+	chordSymbol := prefix + suffix
+	if bass != "" {
+		chordSymbol += "/" + bass
+	}
+	chord, err := ParseChordE(chordSymbol)
+	if parseErr := (*ParseError)(nil); errors.As(err, &parseErr) {
+		parseErr.Input = symbol
+		parseErr.Offset = offset + max(0, parseErr.Offset-len(prefix))
+		parseErr.Token = tokenAt(symbol, parseErr.Offset)
+	}
+	return chord, err
+}
+
+// tokenAt returns the character of s at offset, or an empty string at the end of s
+func tokenAt(s string, offset int) string {
+	tokens := lexChord(s[min(offset, len(s)):])
+	return tokens[0].text
+}
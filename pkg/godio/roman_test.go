@@ -0,0 +1,118 @@
+package godio
+
+import (
+	"fmt"
+	"slices"
+	"testing"
+)
+
+func TestParseKey(t *testing.T) {
+	parameters := []struct {
+		input    string
+		expected Key
+	}{
+		{"C", Key{ParseNote("C"), ModeMajor}},
+		{"Eb major", Key{ParseNote("Eb"), ModeMajor}},
+		{"F#m", Key{ParseNote("F#"), ModeMinor}},
+		{"A minor", Key{ParseNote("A"), ModeMinor}},
+		{"c", Key{ParseNote("C"), ModeMinor}},
+	}
+
+	for _, p := range parameters {
+		t.Run(fmt.Sprintf("Testing %v", p.input), func(t *testing.T) {
+			key, err := ParseKey(p.input)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if key != p.expected {
+				t.Errorf("Expected %v, but got %v", p.expected, key)
+			}
+		})
+	}
+
+	if _, err := ParseKey("H"); err == nil {
+		t.Errorf("Expected an error for an unknown tonic")
+	}
+}
+
+func TestParseRelativeChords(t *testing.T) {
+	parameters := []struct {
+		key      string
+		input    string
+		expected string
+	}{
+		{"C", "ii7", "Dm7"},
+		{"C", "V7", "G7"},
+		{"C", "Imaj7", "Cmaj7"},
+		{"C", "V65", "G7/B"},
+		{"C", "I64", "C/G"},
+		{"C", "bVII", "Bb"},
+		{"C", "vii°7", "Bdim7"},
+		{"C", "iiø7", "Dø7"},
+		{"C", "V7/ii", "A7"},
+		{"C", "V/V/V", "A"},
+		{"Eb", "IV", "Ab"},
+		{"Am", "III", "C"},
+		{"Cm", "viio7", "Bdim7"},
+		{"Cm", "vii°", "Bdim"},
+		{"Cm", "#vii°7", "Bdim7"},
+		{"Cm", "V7", "G7"},
+		{"Cm", "VII", "Bb"},
+		{"Cm", "bVI", "Ab"},
+		{"Cm", "bIII", "Eb"},
+		{"Cm", "bVII7", "Bb7"},
+		{"Cm", "bII", "Db"},
+		{"Cm", "V7/bVI", "Eb7"},
+		{"Bb", "2m7", "Cm7"},
+		{"Bb", "5", "F"},
+		{"Bb", "b7", "Ab"},
+		{"Bb", "1/3", "Bb/D"},
+		{"Bb", "Ebmaj7", "Ebmaj7"},
+	}
+
+	for _, p := range parameters {
+		t.Run(fmt.Sprintf("Testing %v in %v", p.input, p.key), func(t *testing.T) {
+			chord, err := mustParseKey(t, p.key).ParseChord(p.input)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			expected := MustParseChord(p.expected)
+			if !slices.Equal(chord.PitchClasses(), expected.PitchClasses()) || chord.BassNote.PitchClass() != expected.BassNote.PitchClass() {
+				t.Errorf("Expected %s, but got %s", expected, chord)
+			}
+		})
+	}
+}
+
+func TestParseRomanNumeralErrors(t *testing.T) {
+	key := Key{ParseNote("C"), ModeMajor}
+	parameters := []struct {
+		input  string
+		offset int
+	}{
+		{"X", 0},
+		{"V7/x", 3},
+		{"V7bx", 3},
+	}
+
+	for _, p := range parameters {
+		t.Run(fmt.Sprintf("Testing %v", p.input), func(t *testing.T) {
+			_, err := ParseRomanNumeral(key, p.input)
+			parseErr, ok := err.(*ParseError)
+			if !ok {
+				t.Fatalf("Expected a *ParseError, but got %v", err)
+			}
+			if parseErr.Input != p.input || parseErr.Offset != p.offset {
+				t.Errorf("Expected an error at offset %d of %q, but got %v", p.offset, p.input, parseErr)
+			}
+		})
+	}
+}
+
+func mustParseKey(t *testing.T, s string) Key {
+	key, err := ParseKey(s)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	return key
+}
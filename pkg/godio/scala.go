@@ -0,0 +1,172 @@
+package godio
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// LoadScala loads a tuning from a Scala scale file (.scl) and an optional
+// keyboard mapping file (.kbm). Without a mapping, the first degree of the scale
+// is on the tonic in octave 4, which sounds as in equal temperament with A4 at
+// the reference frequency.
+func LoadScala(sclPath string, kbmPath string, tonic PitchClass, reference float64) (*ScaleTuning, error) {
+	sclFile, err := os.Open(sclPath)
+	if err != nil {
+		return nil, err
+	}
+	defer sclFile.Close()
+	tuning, err := ParseScala(sclFile)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", sclPath, err)
+	}
+	tuning.Mapping = tonicMapping(tonic, reference)
+
+	if kbmPath != "" {
+		kbmFile, err := os.Open(kbmPath)
+		if err != nil {
+			return nil, err
+		}
+		defer kbmFile.Close()
+		tuning.Mapping, err = ParseKeyboardMapping(kbmFile)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", kbmPath, err)
+		}
+		if _, ok := tuning.degree(tuning.Mapping.ReferenceNote); !ok {
+			return nil, fmt.Errorf("%s: reference note %d is not mapped", kbmPath, tuning.Mapping.ReferenceNote)
+		}
+	}
+	return tuning, nil
+}
+
+// ParseScala parses a Scala scale file. The returned tuning has no keyboard
+// mapping. A scale of 0 notes has only its first degree, which every key plays.
+func ParseScala(r io.Reader) (*ScaleTuning, error) {
+	lines, err := scalaLines(r)
+	if err != nil {
+		return nil, err
+	}
+	if len(lines) < 2 {
+		return nil, fmt.Errorf("invalid scale: expected a description and a number of notes")
+	}
+
+	count, err := strconv.Atoi(scalaField(lines[1]))
+	if err != nil || count < 0 {
+		return nil, fmt.Errorf("invalid scale: invalid number of notes %q", lines[1])
+	}
+	if len(lines)-2 < count {
+		return nil, fmt.Errorf("invalid scale: expected %d notes, but got %d", count, len(lines)-2)
+	}
+
+	tuning := &ScaleTuning{Description: strings.TrimSpace(lines[0])}
+	for _, line := range lines[2 : 2+count] {
+		cents, err := parseScalaPitch(scalaField(line))
+		if err != nil {
+			return nil, err
+		}
+		tuning.Degrees = append(tuning.Degrees, cents)
+	}
+	return tuning, nil
+}
+
+// parseScalaPitch parses a degree of a Scala scale, in cents when it contains a
+// period and as a ratio such as 3/2 or 2 otherwise.
+func parseScalaPitch(field string) (float64, error) {
+	if strings.Contains(field, ".") {
+		cents, err := strconv.ParseFloat(field, 64)
+		if err != nil {
+			return 0, fmt.Errorf("invalid scale: invalid cents %q", field)
+		}
+		return cents, nil
+	}
+
+	numerator, denominator, isFraction := strings.Cut(field, "/")
+	if !isFraction {
+		denominator = "1"
+	}
+	n, errN := strconv.ParseUint(numerator, 10, 64)
+	d, errD := strconv.ParseUint(denominator, 10, 64)
+	if errN != nil || errD != nil || n == 0 || d == 0 {
+		return 0, fmt.Errorf("invalid scale: invalid ratio %q", field)
+	}
+	return 1200 * math.Log2(float64(n)/float64(d)), nil
+}
+
+// ParseKeyboardMapping parses a Scala keyboard mapping file
+func ParseKeyboardMapping(r io.Reader) (KeyboardMapping, error) {
+	lines, err := scalaLines(r)
+	if err != nil {
+		return KeyboardMapping{}, err
+	}
+	names := []string{"map size", "first note", "last note", "middle note", "reference note", "reference frequency", "octave degree"}
+	if len(lines) < len(names) {
+		return KeyboardMapping{}, fmt.Errorf("invalid keyboard mapping: expected the %s", names[len(lines)])
+	}
+
+	values := make([]int, len(names))
+	for i, name := range names {
+		field := scalaField(lines[i])
+		if name == "reference frequency" {
+			continue
+		}
+		values[i], err = strconv.Atoi(field)
+		if err != nil {
+			return KeyboardMapping{}, fmt.Errorf("invalid keyboard mapping: invalid %s %q", name, field)
+		}
+	}
+	frequency, err := strconv.ParseFloat(scalaField(lines[5]), 64)
+	if err != nil || frequency <= 0 {
+		return KeyboardMapping{}, fmt.Errorf("invalid keyboard mapping: invalid reference frequency %q", lines[5])
+	}
+
+	mapping := KeyboardMapping{
+		First:              values[1],
+		Last:               values[2],
+		Middle:             values[3],
+		ReferenceNote:      values[4],
+		ReferenceFrequency: frequency,
+		OctaveDegree:       values[6],
+	}
+	size := values[0]
+	if size < 0 || len(lines)-len(names) < size {
+		return KeyboardMapping{}, fmt.Errorf("invalid keyboard mapping: expected %d keys", size)
+	}
+	for _, line := range lines[len(names) : len(names)+size] {
+		field := scalaField(line)
+		if field == "x" {
+			mapping.Keys = append(mapping.Keys, -1)
+			continue
+		}
+		degree, err := strconv.Atoi(field)
+		if err != nil || degree < 0 {
+			return KeyboardMapping{}, fmt.Errorf("invalid keyboard mapping: invalid key %q", field)
+		}
+		mapping.Keys = append(mapping.Keys, degree)
+	}
+	return mapping, nil
+}
+
+// scalaLines returns the lines of a Scala file without its comments, which start with !
+func scalaLines(r io.Reader) ([]string, error) {
+	lines := []string{}
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		if !strings.HasPrefix(scanner.Text(), "!") {
+			lines = append(lines, scanner.Text())
+		}
+	}
+	return lines, scanner.Err()
+}
+
+// scalaField returns the first field of a line of a Scala file, ignoring any text after it
+func scalaField(line string) string {
+	fields := strings.Fields(line)
+	if len(fields) == 0 {
+		return ""
+	}
+	return fields[0]
+}
@@ -0,0 +1,195 @@
+package godio
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/samber/lo"
+)
+
+// Scale is a scale or mode built on a tonic, such as D dorian
+type Scale struct {
+	Tonic     SpelledNote
+	Name      string
+	Intervals []int // Semitones above the tonic of each degree, starting with 0
+}
+
+// scaleFormulas are the semitones above the tonic of the degrees of each scale.
+// scaleNames lists them in the order in which Chord.AvailableScales prefers them.
+var scaleFormulas = map[string][]int{
+	// Modes of the major scale
+	"ionian":     {0, 2, 4, 5, 7, 9, 11},
+	"dorian":     {0, 2, 3, 5, 7, 9, 10},
+	"phrygian":   {0, 1, 3, 5, 7, 8, 10},
+	"lydian":     {0, 2, 4, 6, 7, 9, 11},
+	"mixolydian": {0, 2, 4, 5, 7, 9, 10},
+	"aeolian":    {0, 2, 3, 5, 7, 8, 10},
+	"locrian":    {0, 1, 3, 5, 6, 8, 10},
+	// Modes of the melodic minor scale
+	"melodic-minor":    {0, 2, 3, 5, 7, 9, 11},
+	"dorian-b2":        {0, 1, 3, 5, 7, 9, 10},
+	"lydian-augmented": {0, 2, 4, 6, 8, 9, 11},
+	"lydian-dominant":  {0, 2, 4, 6, 7, 9, 10},
+	"mixolydian-b6":    {0, 2, 4, 5, 7, 8, 10},
+	"locrian-#2":       {0, 2, 3, 5, 6, 8, 10},
+	"altered":          {0, 1, 3, 4, 6, 8, 10},
+	// Modes of the harmonic minor scale
+	"harmonic-minor":    {0, 2, 3, 5, 7, 8, 11},
+	"locrian-#6":        {0, 1, 3, 5, 6, 9, 10},
+	"ionian-#5":         {0, 2, 4, 5, 8, 9, 11},
+	"dorian-#4":         {0, 2, 3, 6, 7, 9, 10},
+	"phrygian-dominant": {0, 1, 4, 5, 7, 8, 10},
+	"lydian-#2":         {0, 3, 4, 6, 7, 9, 11},
+	"ultralocrian":      {0, 1, 3, 4, 6, 8, 9},
+	// Symmetric scales
+	"diminished":          {0, 2, 3, 5, 6, 8, 9, 11}, // Whole-half
+	"dominant-diminished": {0, 1, 3, 4, 6, 7, 9, 10}, // Half-whole
+	"whole-tone":          {0, 2, 4, 6, 8, 10},
+	// Bebop scales, with a chromatic passing tone
+	"bebop-dominant": {0, 2, 4, 5, 7, 9, 10, 11},
+	"bebop-major":    {0, 2, 4, 5, 7, 8, 9, 11},
+	"bebop-dorian":   {0, 2, 3, 4, 5, 7, 9, 10},
+	// Pentatonic and blues scales
+	"major-pentatonic": {0, 2, 4, 7, 9},
+	"minor-pentatonic": {0, 3, 5, 7, 10},
+	"blues":            {0, 3, 5, 6, 7, 10},
+	"major-blues":      {0, 2, 3, 4, 7, 9},
+}
+
+var scaleNames = []string{
+	"ionian", "dorian", "phrygian", "lydian", "mixolydian", "aeolian", "locrian",
+	"melodic-minor", "dorian-b2", "lydian-augmented", "lydian-dominant", "mixolydian-b6", "locrian-#2", "altered",
+	"harmonic-minor", "locrian-#6", "ionian-#5", "dorian-#4", "phrygian-dominant", "lydian-#2", "ultralocrian",
+	"diminished", "dominant-diminished", "whole-tone",
+	"bebop-dominant", "bebop-major", "bebop-dorian",
+	"major-pentatonic", "minor-pentatonic", "blues", "major-blues",
+}
+
+// scaleAliases are other names of the scales of scaleFormulas
+var scaleAliases = map[string]string{
+	"major":           "ionian",
+	"minor":           "aeolian",
+	"natural-minor":   "aeolian",
+	"half-diminished": "locrian-#2",
+	"super-locrian":   "altered",
+	"lydian-b7":       "lydian-dominant",
+	"whole-half":      "diminished",
+	"half-whole":      "dominant-diminished",
+	"spanish":         "phrygian-dominant",
+	"pentatonic":      "major-pentatonic",
+}
+
+// ScaleNames returns the names of the known scales, modes of the major scale first
+func ScaleNames() []string {
+	return slices.Clone(scaleNames)
+}
+
+// NewScale returns the scale of a name of ScaleNames, or one of its aliases such
+// as "major", "minor" or "half-whole", built on a tonic
+func NewScale(tonic SpelledNote, name string) (*Scale, error) {
+	name = strings.ToLower(name)
+	if alias, ok := scaleAliases[name]; ok {
+		name = alias
+	}
+	intervals, ok := scaleFormulas[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown scale %q, expected one of %s", name, strings.Join(scaleNames, ", "))
+	}
+	return &Scale{Tonic: tonic, Name: name, Intervals: slices.Clone(intervals)}, nil
+}
+
+// ParseScale parses a tonic followed by a scale name, such as "D dorian" or "Bb
+// altered". The scale is major when only the tonic is given.
+func ParseScale(s string) (*Scale, error) {
+	tonicName, name, _ := strings.Cut(strings.TrimSpace(s), " ")
+	tonic, err := ParseNoteE(tonicName)
+	if err != nil {
+		return nil, fmt.Errorf("invalid scale %q: %w", s, err)
+	}
+	name = strings.Join(strings.Fields(name), "-")
+	if name == "" {
+		name = "major"
+	}
+	return NewScale(tonic, name)
+}
+
+func (s Scale) String() string {
+	return fmt.Sprintf("%s %s", s.Tonic, s.Name)
+}
+
+// Notes returns the notes of the scale from the tonic. Scales of seven notes use
+// every letter once, as in F Gb Ab Bbb Cb Db Ebb for F ultralocrian. Other scales
+// spell their notes as SpelledNote.Add, except that scales without a fourth have
+// an augmented fourth, and an augmented fifth when they have no fifth either, as
+// in C D E F# G# Bb for C whole-tone.
+func (s Scale) Notes() []SpelledNote {
+	hasFourth, hasFifth := slices.Contains(s.Intervals, 5), slices.Contains(s.Intervals, 7)
+	return lo.Map(s.Intervals, func(interval int, degree int) SpelledNote {
+		steps := Semitones(interval).Steps
+		switch {
+		case len(s.Intervals) == 7:
+			steps = degree
+		case interval == 6 && !hasFourth:
+			steps = 3
+		case interval == 8 && !hasFourth && !hasFifth:
+			steps = 4
+		}
+		return spellPitchClass((s.Tonic.letterIndex()+steps)%7, s.Tonic.PitchClass().Add(Semitones(interval)))
+	})
+}
+
+// PitchClasses returns the pitch classes of the notes of the scale from the tonic
+func (s Scale) PitchClasses() []int {
+	return lo.Map(s.Intervals, func(interval int, _ int) int {
+		return int(s.Tonic.PitchClass().Add(Semitones(interval)))
+	})
+}
+
+// Pitches returns the pitches of the scale going up from the tonic in an octave
+// to the tonic an octave above
+func (s Scale) Pitches(octave int) []Pitch {
+	tonic := s.Tonic.Pitch(octave).MIDI()
+	pitches := lo.Map(s.Notes(), func(note SpelledNote, degree int) Pitch {
+		// The octave follows the letter, as in Cb5 a major seventh above Db4
+		natural := naturalNoteNumber[note.letterIndex()] + note.Accidental
+		return Pitch{Note: note, Octave: floorDiv(tonic+s.Intervals[degree]-natural, 12) - 1}
+	})
+	return append(pitches, s.Tonic.Pitch(octave+1))
+}
+
+// Contains reports whether a pitch class belongs to the scale
+func (s Scale) Contains(pitchClass PitchClass) bool {
+	return slices.Contains(s.PitchClasses(), int(pitchClass))
+}
+
+// AvailableScales returns the scales built on the root of the chord that contain
+// every chord tone and the bass note. They are sorted by their number of avoid
+// notes, the scale notes a half step above a chord tone, allowing one in the
+// modes of the major scale as the fourth of C ionian over Cmaj7, then in the
+// order of ScaleNames. Dm7 gives D dorian first, Cdim7 C diminished and C7alt
+// only C altered.
+func (c Chord) AvailableScales() []*Scale {
+	chordTones := c.PitchClasses()
+	avoidNotes := func(scale *Scale) int {
+		avoid := lo.CountBy(scale.PitchClasses(), func(pitchClass int) bool {
+			return !slices.Contains(chordTones, pitchClass) && slices.Contains(chordTones, mod12(pitchClass-1))
+		})
+		if slices.Index(scaleNames, scale.Name) < 7 {
+			avoid = max(0, avoid-1)
+		}
+		return avoid
+	}
+
+	scales := []*Scale{}
+	for _, name := range scaleNames {
+		scale, _ := NewScale(c.Root, name)
+		if lo.Every(scale.PitchClasses(), chordTones) {
+			scales = append(scales, scale)
+		}
+	}
+	slices.SortStableFunc(scales, func(a, b *Scale) int {
+		return avoidNotes(a) - avoidNotes(b)
+	})
+	return scales
+}
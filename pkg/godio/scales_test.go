@@ -0,0 +1,114 @@
+package godio
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/samber/lo"
+)
+
+func TestParseScale(t *testing.T) {
+	parameters := []struct {
+		input    string
+		expected string
+	}{
+		{"D dorian", "D E F G A B C"},
+		{"C", "C D E F G A B"},
+		{"A minor", "A B C D E F G"},
+		{"Eb lydian dominant", "Eb F G A Bb C Db"},
+		{"C altered", "C Db Eb Fb Gb Ab Bb"},
+		{"F ultralocrian", "F Gb Ab Bbb Cb Db Ebb"},
+		{"F# harmonic-minor", "F# G# A B C# D E#"},
+		{"C whole tone", "C D E F# G# Bb"},
+		{"C half-whole", "C Db Eb E F# G A Bb"},
+		{"C diminished", "C D Eb F Gb Ab A B"},
+		{"G bebop dominant", "G A B C D E F F#"},
+		{"A minor pentatonic", "A C D E G"},
+		{"C blues", "C Eb F Gb G Bb"},
+	}
+
+	for _, p := range parameters {
+		t.Run(fmt.Sprintf("Testing %v", p.input), func(t *testing.T) {
+			scale, err := ParseScale(p.input)
+			if err != nil {
+				t.Fatalf("Expected %v, but got %v", p.expected, err)
+			}
+			notes := strings.Join(lo.Map(scale.Notes(), func(note SpelledNote, _ int) string { return note.String() }), " ")
+			if notes != p.expected {
+				t.Errorf("Expected %v, but got %v", p.expected, notes)
+			}
+		})
+	}
+
+	for _, input := range []string{"H dorian", "D dorianish", ""} {
+		if _, err := ParseScale(input); err == nil {
+			t.Errorf("Expected an error for %q, but got nil", input)
+		}
+	}
+}
+
+func TestScalePitches(t *testing.T) {
+	parameters := []struct {
+		scale    string
+		octave   int
+		expected string
+	}{
+		{"D dorian", 4, "D4 E4 F4 G4 A4 B4 C5 D5"},
+		{"B major", 3, "B3 C#4 D#4 E4 F#4 G#4 A#4 B4"},
+		{"Db altered", 4, "Db4 Ebb4 Fb4 Gbb4 Abb4 Bbb4 Cb5 Db5"},
+	}
+
+	for _, p := range parameters {
+		t.Run(fmt.Sprintf("Testing %v", p.scale), func(t *testing.T) {
+			scale, _ := ParseScale(p.scale)
+			pitches := scale.Pitches(p.octave)
+			if names := fmt.Sprint(pitches); names != "["+p.expected+"]" {
+				t.Errorf("Expected %v, but got %v", p.expected, names)
+			}
+			for i := 1; i < len(pitches); i++ {
+				if pitches[i].MIDI() <= pitches[i-1].MIDI() {
+					t.Errorf("Expected ascending pitches, but got %v", pitches)
+				}
+			}
+		})
+	}
+}
+
+func TestAvailableScales(t *testing.T) {
+	parameters := []struct {
+		chord    string
+		expected []string
+	}{
+		{"Cmaj7", []string{"C ionian", "C lydian"}},
+		{"Dm7", []string{"D dorian", "D aeolian"}},
+		{"G7", []string{"G mixolydian", "G lydian-dominant"}},
+		{"C7alt", []string{"C altered"}},
+		{"C7b9", []string{"C dominant-diminished", "C phrygian-dominant"}},
+		{"Bø7", []string{"B locrian", "B locrian-#2"}},
+		{"Bm7b5", []string{"B locrian", "B locrian-#2"}},
+		{"C7#5", []string{"C whole-tone", "C mixolydian-b6"}},
+		{"C7b5", []string{"C whole-tone", "C lydian-dominant"}},
+		{"Cdim7", []string{"C diminished"}},
+		{"Caug", []string{"C whole-tone"}},
+		{"Cmaj7#11", []string{"C lydian", "C lydian-#2"}},
+	}
+
+	for _, p := range parameters {
+		t.Run(fmt.Sprintf("Testing %v", p.chord), func(t *testing.T) {
+			chord := MustParseChord(p.chord)
+			scales := lo.Map(chord.AvailableScales(), func(scale *Scale, _ int) string { return scale.String() })
+			if len(scales) < len(p.expected) || !slices.Equal(scales[:len(p.expected)], p.expected) {
+				t.Errorf("Expected %v first, but got %v", p.expected, scales)
+			}
+			for _, scale := range chord.AvailableScales() {
+				for _, pitchClass := range chord.PitchClasses() {
+					if !scale.Contains(PitchClass(pitchClass)) {
+						t.Errorf("Expected %v to contain %v", scale, PitchClass(pitchClass))
+					}
+				}
+			}
+		})
+	}
+}
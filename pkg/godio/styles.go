@@ -0,0 +1,265 @@
+package godio
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/samber/lo"
+)
+
+type VoicingStyle string
+
+const (
+	VoicingClose     VoicingStyle = "close"      // Chord tones stacked within an octave
+	VoicingDrop2     VoicingStyle = "drop2"      // Close position with the second voice from the top dropped an octave
+	VoicingDrop3     VoicingStyle = "drop3"      // Close position with the third voice from the top dropped an octave
+	VoicingDrop24    VoicingStyle = "drop2and4"  // Close position with the second and fourth voices from the top dropped an octave
+	VoicingShell     VoicingStyle = "shell"      // Bud Powell shell: the third and seventh over the bass
+	VoicingRootlessA VoicingStyle = "rootless-a" // Bill Evans A form: 3-5-7-9, or 3-13-7-9 on dominants
+	VoicingRootlessB VoicingStyle = "rootless-b" // Bill Evans B form: 7-9-3-5, or 7-9-3-13 on dominants
+	VoicingQuartal   VoicingStyle = "quartal"    // Chord tones stacked in fourths, topped by a major third as in "So What", or close position when they do not stack
+	VoicingSpread    VoicingStyle = "spread"     // Open voicing with the fifth and seventh low and the third and tensions above
+)
+
+// voicingStyles build the upper voices of a chord in each style as ascending MIDI notes.
+// The notes are moved above the bass by Chord.Voice.
+var voicingStyles = map[VoicingStyle]func(c Chord) []int{
+	VoicingClose: Chord.closeUpperVoices,
+	VoicingDrop2: func(c Chord) []int {
+		return dropVoices(c.closeFourVoices(), 2)
+	},
+	VoicingDrop3: func(c Chord) []int {
+		return dropVoices(c.closeFourVoices(), 3)
+	},
+	VoicingDrop24: func(c Chord) []int {
+		return dropVoices(c.closeFourVoices(), 2, 4)
+	},
+	VoicingShell: func(c Chord) []int {
+		tones := c.guideTones()
+		return stackNotes(ParsePitch("C3").MIDI(), c.pitchClassesOf(presentTones(tones.third, tones.seventh)...)...)
+	},
+	VoicingRootlessA: func(c Chord) []int {
+		tones := c.guideTones()
+		return stackNotes(ParsePitch("D3").MIDI(), c.pitchClassesOf(presentTones(tones.third, tones.fifth, tones.seventh, tones.ninth)...)...)
+	},
+	VoicingRootlessB: func(c Chord) []int {
+		tones := c.guideTones()
+		return stackNotes(ParsePitch("D3").MIDI(), c.pitchClassesOf(presentTones(tones.seventh, tones.ninth, tones.third, tones.fifth)...)...)
+	},
+	VoicingQuartal: func(c Chord) []int {
+		stack := c.quartalStack()
+		if stack == nil {
+			return c.closeUpperVoices()
+		}
+		return stackNotes(ParsePitch("C3").MIDI(), c.pitchClassesOf(stack...)...)
+	},
+	VoicingSpread: func(c Chord) []int {
+		tones := c.guideTones()
+		intervals := presentTones(tones.fifth, tones.seventh, tones.third)
+		if !tones.hasSeventh {
+			intervals = presentTones(0, tones.fifth, tones.third)
+		}
+		for _, tone := range c.intervalSet() {
+			if !slices.Contains(intervals, tone) && tone != 0 && tone != 7 {
+				intervals = append(intervals, tone)
+			}
+		}
+		return stackNotes(ParsePitch("C3").MIDI(), c.pitchClassesOf(intervals...)...)
+	},
+}
+
+// ParseVoicingStyle parses the name of a VoicingStyle
+func ParseVoicingStyle(s string) (VoicingStyle, error) {
+	style := VoicingStyle(strings.ToLower(s))
+	if _, ok := voicingStyles[style]; !ok {
+		names := lo.Map(lo.Keys(voicingStyles), func(style VoicingStyle, _ int) string { return string(style) })
+		slices.Sort(names)
+		return "", fmt.Errorf("unknown voicing style %q, expected one of %s", s, strings.Join(names, ", "))
+	}
+	return style, nil
+}
+
+// Voice returns the voicing of the chord in a style, with the bass note between
+// E2 and D#3, or an octave lower when the upper voices would not be above it.
+// The style applies to the lower chord of a polychord, and the upper structure is
+// voiced in close position above it. The constraints of the chord are then
+// applied. Unknown styles, which ParseVoicingStyle rejects, are voiced in close
+// position, so that setting Chord.Style never breaks GetFrequencies.
+func (c Chord) Voice(style VoicingStyle) Voicing {
+	build, ok := voicingStyles[style]
+	if !ok {
+		build = voicingStyles[VoicingClose]
+	}
+	bass := ParsePitch("E2").MIDI()
+	bass += mod12(int(c.bass().PitchClass()) - bass)
+	upper := build(c.Lower())
+	for len(upper) > 0 && upper[0] <= bass {
+		bass -= 12
+	}
+	voicing := append(Voicing{bass}, upper...)
+	if c.Upper != nil {
+		voicing = append(voicing, c.Upper.voiceAbove(slices.Max(voicing))...)
+	}
+	return c.constrain(voicing)
+}
+
+// noTone marks a guide tone the chord does not have
+const noTone = -1
+
+// chordGuideTones are the intervals above the root of the tones used to build
+// the voicing styles, noTone for the ones the chord does not have.
+type chordGuideTones struct {
+	third      int // Major or minor third, or the suspended fourth or second
+	fifth      int // Fifth, altered fifth, or thirteenth on dominant chords
+	seventh    int // Seventh, or sixth in sixth chords
+	ninth      int // Ninth, altered ninth, or added fourth or eleventh
+	hasSeventh bool
+}
+
+// guideTones finds the guide tones among the tones of the chord, so that
+// omitted tones are left out and added ones are kept
+func (c Chord) guideTones() chordGuideTones {
+	set := c.intervalSet()
+	first := func(candidates ...int) int {
+		for _, candidate := range candidates {
+			if slices.Contains(set, candidate) {
+				return candidate
+			}
+		}
+		return noTone
+	}
+
+	tones := chordGuideTones{}
+	tones.third = first(4, 3, 5, 2)
+	tones.seventh = first(10, 11, 9)
+	tones.hasSeventh = tones.seventh == 10 || tones.seventh == 11 || c.Quality == "dim7"
+	tones.fifth = first(7, 6, 8)
+	if tones.seventh == 10 && slices.Contains(set, 9) {
+		tones.fifth = 9
+	}
+	tones.ninth = first(2, 1)
+	if tones.ninth == noTone && tones.third == 4 && slices.Contains(set, 3) {
+		tones.ninth = 3
+	}
+	if tones.ninth == noTone && tones.third != 5 {
+		tones.ninth = first(5, 6)
+	}
+	if tones.ninth == tones.fifth {
+		tones.ninth = noTone
+	}
+	return tones
+}
+
+// presentTones returns the tones that are not noTone
+func presentTones(tones ...int) []int {
+	return lo.Without(tones, noTone)
+}
+
+// intervalSet returns the sorted intervals above the root, from 0 to 11, of the chord tones
+func (c Chord) intervalSet() []int {
+	set := lo.Uniq(lo.Map(c.Tones, func(tone int, _ int) int { return mod12(tone) }))
+	slices.Sort(set)
+	return set
+}
+
+// pitchClassesOf returns the pitch classes of intervals above the root
+func (c Chord) pitchClassesOf(intervals ...int) []int {
+	return lo.Map(intervals, func(interval int, _ int) int {
+		return int(c.Root.PitchClass().Add(Semitones(interval)))
+	})
+}
+
+// rootPositionClasses sorts pitch classes by their interval above the root
+func (c Chord) rootPositionClasses(pitchClasses []int) []int {
+	root := int(c.Root.PitchClass())
+	sorted := slices.Clone(pitchClasses)
+	slices.SortFunc(sorted, func(a, b int) int {
+		return mod12(a-root) - mod12(b-root)
+	})
+	return sorted
+}
+
+// closeUpperVoices returns the chord tones in root position from G3
+func (c Chord) closeUpperVoices() []int {
+	return stackNotes(ParsePitch("G3").MIDI(), c.rootPositionClasses(c.upperPitchClasses())...)
+}
+
+// quartalStack returns the longest chain of chord tones a perfect fourth apart,
+// as intervals above the root, topped by the chord tone a major third above
+// when there is one, as in "So What". Chains starting on the third, then on
+// the root, are preferred among chains of the same length. It returns nil when
+// no three chord tones are a fourth apart, as they do not sound quartal.
+func (c Chord) quartalStack() []int {
+	set := c.intervalSet()
+	starts := presentTones(c.guideTones().third)
+	for _, tone := range set {
+		if !slices.Contains(starts, tone) {
+			starts = append(starts, tone)
+		}
+	}
+
+	var longest []int
+	for _, start := range starts {
+		stack := []int{start}
+		for next := mod12(start + PerfectFourth.Semitones); slices.Contains(set, next) && !slices.Contains(stack, next); next = mod12(next + PerfectFourth.Semitones) {
+			stack = append(stack, next)
+		}
+		if len(stack) < 3 {
+			continue
+		}
+		if top := mod12(stack[len(stack)-1] + MajorThird.Semitones); slices.Contains(set, top) && !slices.Contains(stack, top) {
+			stack = append(stack, top)
+		}
+		if len(stack) > len(longest) {
+			longest = stack
+		}
+	}
+	return longest
+}
+
+// closeFourVoices returns the chord in close position from F3 with four voices.
+// Triads double the root on top, and larger chords keep the third, the fifth or
+// thirteenth, the seventh and the ninth. Chords without tones have no voices.
+func (c Chord) closeFourVoices() []int {
+	intervals := c.intervalSet()
+	if len(intervals) > 4 {
+		tones := c.guideTones()
+		intervals = presentTones(tones.third, tones.fifth, tones.seventh, tones.ninth)
+		slices.Sort(intervals)
+	}
+	pitchClasses := c.pitchClassesOf(intervals...)
+	if len(pitchClasses) == 0 {
+		return nil
+	}
+	if len(pitchClasses) < 4 {
+		pitchClasses = append(pitchClasses, pitchClasses[0])
+	}
+	return stackNotes(ParsePitch("F3").MIDI(), pitchClasses...)
+}
+
+// dropVoices lowers by an octave the voices at the given positions counted from
+// the top, 1 being the highest, and returns the notes in ascending order.
+func dropVoices(notes []int, positions ...int) []int {
+	dropped := slices.Clone(notes)
+	for _, position := range positions {
+		if position <= len(dropped) {
+			dropped[len(dropped)-position] -= 12
+		}
+	}
+	slices.Sort(dropped)
+	return dropped
+}
+
+// stackNotes places each pitch class on the closest note above the previous one,
+// starting from the lowest note
+func stackNotes(lowest int, pitchClasses ...int) []int {
+	notes := []int{}
+	next := lowest
+	for _, pitchClass := range pitchClasses {
+		note := next + mod12(pitchClass-next)
+		notes = append(notes, note)
+		next = note + 1
+	}
+	return notes
+}
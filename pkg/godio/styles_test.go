@@ -0,0 +1,102 @@
+package godio
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/samber/lo"
+)
+
+func TestVoicingStyles(t *testing.T) {
+	parameters := []struct {
+		chord    string
+		style    VoicingStyle
+		expected string
+	}{
+		{"Cmaj7", VoicingClose, "C3 C4 E4 G4 B4"},
+		{"G7", VoicingClose, "G2 G3 B3 D4 F4"},
+		{"Cmaj7", VoicingDrop2, "C3 G3 C4 E4 B4"},
+		{"G7", VoicingDrop2, "G2 D3 G3 B3 F4"},
+		{"Cmaj7", VoicingDrop3, "C3 E3 C4 G4 B4"},
+		{"G7", VoicingDrop24, "G1 G2 D3 B3 F4"},
+		{"C", VoicingDrop2, "C3 G3 C4 E4 C5"},
+		{"Cmaj7", VoicingShell, "C3 E3 B3"},
+		{"G7", VoicingShell, "G2 B3 F4"},
+		{"C6", VoicingShell, "C3 E3 A3"},
+		{"Dm9", VoicingRootlessA, "D3 F3 A3 C4 E4"},
+		{"G13", VoicingRootlessA, "G2 B3 E4 F4 A4"},
+		{"Dm9", VoicingRootlessB, "D3 C4 E4 F4 A4"},
+		{"G13", VoicingRootlessB, "G2 F3 A3 B3 E4"},
+		{"Dm11", VoicingQuartal, "D3 E3 A3 D4 G4 C5 F5"},
+		{"C69", VoicingQuartal, "C3 E3 A3 D4 G4 C5"},
+		{"C7sus4", VoicingQuartal, "C3 G3 C4 F4 A#4"},
+		{"G7alt", VoicingQuartal, "G2 A#3 D#4 G#4 C#5 F5"},
+		{"Dm7", VoicingQuartal, "D3 D4 F4 A4 C5"},
+		{"Cmaj7", VoicingQuartal, "C3 C4 E4 G4 B4"},
+		{"Cø7", VoicingQuartal, "C3 C4 D#4 F#4 A#4"},
+		{"Cdim", VoicingQuartal, "C3 C4 D#4 F#4"},
+		{"Cmmaj7", VoicingQuartal, "C3 C4 D#4 G4 B4"},
+		{"Caug", VoicingQuartal, "C3 C4 E4 G#4"},
+		{"C7b9", VoicingQuartal, "C3 C#4 E4 G4 A#4"},
+		{"C5", VoicingQuartal, "C3 C4 G4"},
+		{"C(no1,no3,no5)", VoicingQuartal, "C3"},
+		{"Cmaj7", VoicingSpread, "C3 G3 B3 E4"},
+		{"G13", VoicingSpread, "G2 E3 F3 B3 A4"},
+		{"Dm7", VoicingRootlessA, "D3 F3 A3 C4"},
+		{"Cadd4", VoicingRootlessA, "C3 E3 G3 F4"},
+		{"C(no3)", VoicingShell, "C3"},
+		{"C5", VoicingShell, "C3"},
+		{"C(no1,no3,no5)", VoicingDrop2, "C3"},
+		{"C(no1,no3,no5)", VoicingDrop24, "C3"},
+		{"D/C7", VoicingDrop2, "C3 G3 C4 E4 A#4 D5 F#5 A5"},
+		{"D/C7", VoicingShell, "C3 E3 A#3 D4 F#4 A4"},
+	}
+
+	for _, p := range parameters {
+		t.Run(fmt.Sprintf("Testing %v in %v", p.chord, p.style), func(t *testing.T) {
+			voicing := MustParseChord(p.chord).Voice(p.style)
+			names := strings.Join(lo.Map(voicing, func(note int, _ int) string { return PitchFromMIDI(note).String() }), " ")
+			if names != p.expected {
+				t.Errorf("Expected %v, but got %v", p.expected, names)
+			}
+		})
+	}
+}
+
+func TestVoicingStylesWithVoicer(t *testing.T) {
+	chords := lo.Map(strings.Fields("Dm7 G7 Cmaj7 A7"), func(symbol string, _ int) *Chord {
+		return MustParseChord(symbol)
+	})
+
+	for style := range voicingStyles {
+		t.Run(fmt.Sprintf("Testing %v", style), func(t *testing.T) {
+			voicer := NewVoicer()
+			voicer.Style = style
+			for i, voicing := range voicer.Voice(chords) {
+				shape := chords[i].Voice(style)[1:]
+				upper := voicing[1:]
+				if len(upper) != len(shape) || (upper[0]-shape[0])%12 != 0 || !slices.Equal(lo.Map(upper, func(note int, _ int) int { return note - upper[0] }), lo.Map(shape, func(note int, _ int) int { return note - shape[0] })) {
+					t.Errorf("Expected %v voiced as %v moved by octaves, but got %v", chords[i], shape, upper)
+				}
+			}
+		})
+	}
+
+	if _, err := ParseVoicingStyle("Drop2"); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	if _, err := ParseVoicingStyle("drop5"); err == nil {
+		t.Errorf("Expected an error for an unknown voicing style")
+	}
+}
+
+func TestUnknownVoicingStyle(t *testing.T) {
+	chord := MustParseChord("G7")
+	chord.Style = "foo"
+	expected := chord.Voice(VoicingClose).Frequencies(StandardTuning)
+	if frequencies := chord.GetFrequencies(); !slices.Equal(frequencies, expected) {
+		t.Errorf("Expected the unknown style voiced in close position as %v, but got %v", expected, frequencies)
+	}
+}
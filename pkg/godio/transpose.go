@@ -0,0 +1,219 @@
+package godio
+
+import (
+	"slices"
+	"strings"
+)
+
+// SpellingPolicy chooses the name of a pitch class, from 0 for C to 11 for B
+type SpellingPolicy interface {
+	Spell(pitchClass PitchClass) SpelledNote
+}
+
+type notesSpelling []SpelledNote
+
+func (n notesSpelling) Spell(pitchClass PitchClass) SpelledNote {
+	return n[mod12(int(pitchClass))]
+}
+
+var (
+	SharpSpelling SpellingPolicy = notesSpelling(sharpNotes) // C#, D#, F#, G#, A#
+	FlatSpelling  SpellingPolicy = notesSpelling(flatNotes)  // Db, Eb, Gb, Ab, Bb
+)
+
+type keySpelling struct {
+	key Key
+}
+
+// KeySpelling spells the notes of the scale of a key with its key signature,
+// such as Bb and Eb in Eb major or E# in C# major. Notes outside the scale use
+// flats in flat keys and sharps otherwise.
+func KeySpelling(k Key) SpellingPolicy {
+	return keySpelling{k}
+}
+
+func (s keySpelling) Spell(pitchClass PitchClass) SpelledNote {
+	for degree, semitones := range scaleDegrees[s.key.Mode] {
+		if int(s.key.Tonic.PitchClass().Add(Semitones(semitones))) == mod12(int(pitchClass)) {
+			return s.key.degreeNote(degree+1, 0)
+		}
+	}
+	if s.key.usesFlats() {
+		return FlatSpelling.Spell(pitchClass)
+	}
+	return SharpSpelling.Spell(pitchClass)
+}
+
+// usesFlats reports whether the key signature of the key has flats
+func (k Key) usesFlats() bool {
+	for degree := 1; degree <= 7; degree++ {
+		if k.degreeNote(degree, 0).Accidental < 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// Transpose returns a copy of the chord moved by a number of semitones with its
+// root and bass note named by spelling. A nil spelling keeps flats for chords
+// whose root is written with a flat and uses sharps otherwise.
+func (c Chord) Transpose(semitones int, spelling SpellingPolicy) *Chord {
+	if spelling == nil {
+		spelling = SharpSpelling
+		if c.Root.Accidental < 0 {
+			spelling = FlatSpelling
+		}
+	}
+
+	transposed := c
+	transposed.Root = spelling.Spell(c.Root.PitchClass().Add(Semitones(semitones)))
+	if !c.BassNote.IsZero() {
+		transposed.BassNote = spelling.Spell(c.BassNote.PitchClass().Add(Semitones(semitones)))
+	}
+	transposed.Extensions = slices.Clone(c.Extensions)
+	transposed.Omissions = slices.Clone(c.Omissions)
+	transposed.Tones = slices.Clone(c.Tones)
+	if c.Upper != nil {
+		transposed.Upper = c.Upper.Transpose(semitones, spelling)
+	}
+	return &transposed
+}
+
+// Progression is a sequence of chords
+type Progression []*Chord
+
+func (p Progression) String() string {
+	symbols := make([]string, len(p))
+	for i, chord := range p {
+		symbols[i] = chord.String()
+	}
+	return strings.Join(symbols, " ")
+}
+
+// Transpose returns the progression moved by a number of semitones. Chords are
+// spelled with KeySpelling for the estimated key of the progression once
+// transposed, so that a progression in Eb major keeps flats while one in E major
+// uses sharps.
+func (p Progression) Transpose(semitones int) Progression {
+	key := p.EstimateKey()
+	target := Key{Tonic: keyTonics[key.Mode][key.Tonic.PitchClass().Add(Semitones(semitones))], Mode: key.Mode}
+	return p.TransposeWith(semitones, KeySpelling(target))
+}
+
+// TransposeWith returns the progression moved by a number of semitones with every chord named by spelling
+func (p Progression) TransposeWith(semitones int, spelling SpellingPolicy) Progression {
+	transposed := make(Progression, len(p))
+	for i, chord := range p {
+		transposed[i] = chord.Transpose(semitones, spelling)
+	}
+	return transposed
+}
+
+// keyTonics are the usual tonics of each key by pitch class, avoiding keys with
+// more than six sharps or flats.
+var keyTonics = map[KeyMode][]SpelledNote{
+	ModeMajor: parseNotes([]string{"C", "Db", "D", "Eb", "E", "F", "F#", "G", "Ab", "A", "Bb", "B"}),
+	ModeMinor: parseNotes([]string{"C", "C#", "D", "Eb", "E", "F", "F#", "G", "G#", "A", "Bb", "B"}),
+}
+
+// diatonicTriads are the qualities of the triads built on each degree of the
+// major and natural minor scales. The minor key also accepts a major dominant.
+var diatonicTriads = map[KeyMode][][]string{
+	ModeMajor: {{"maj"}, {"m"}, {"m"}, {"maj"}, {"maj"}, {"m"}, {"dim"}},
+	ModeMinor: {{"m"}, {"dim"}, {"maj"}, {"m"}, {"m", "maj"}, {"maj"}, {"maj"}},
+}
+
+// KeyScore is a key with how well the chords of a progression fit it
+type KeyScore struct {
+	Key   Key `json:"key"`
+	Score int `json:"score"`
+}
+
+// KeyScores returns every major and minor key scored by how well its diatonic
+// triads match the chords of the progression, best first. Chords starting or
+// ending the progression on the tonic weigh more, as do tonic chords following
+// a major dominant, and ties favor major keys.
+func (p Progression) KeyScores() []KeyScore {
+	scores := []KeyScore{}
+	for _, mode := range []KeyMode{ModeMajor, ModeMinor} {
+		for tonic := 0; tonic < 12; tonic++ {
+			key := Key{Tonic: keyTonics[mode][tonic], Mode: mode}
+			score := 0
+			for i, chord := range p {
+				degree, diatonic := key.chordDegree(chord)
+				if degree == 0 {
+					continue
+				}
+				score++
+				if diatonic {
+					score++
+				}
+				if degree == 1 && diatonic && (i == 0 || i == len(p)-1) {
+					score += 2
+				}
+				if i > 0 && degree == 1 && diatonic && key.isCadence(p[i-1]) {
+					score += 2
+				}
+			}
+			scores = append(scores, KeyScore{Key: key, Score: score})
+		}
+	}
+	slices.SortStableFunc(scores, func(a, b KeyScore) int {
+		return b.Score - a.Score
+	})
+	return scores
+}
+
+// EstimateKey returns the key whose diatonic triads best match the chords of
+// the progression, the first of KeyScores.
+func (p Progression) EstimateKey() Key {
+	return p.KeyScores()[0].Key
+}
+
+// isCadence reports whether a chord is the major dominant of the key, so that
+// it makes a cadence when followed by the tonic
+func (k Key) isCadence(c *Chord) bool {
+	degree, _ := k.chordDegree(c)
+	return degree == 5 && c.triadQuality() == "maj"
+}
+
+// chordDegree returns the scale degree, from 1 to 7, of the root of a chord in
+// the key or 0 when the root is not in the scale. It also reports whether the
+// chord triad is the diatonic triad of that degree and its seventh, if any, is
+// in the scale.
+func (k Key) chordDegree(c *Chord) (int, bool) {
+	interval := k.interval(c.Root)
+	degree := slices.Index(scaleDegrees[k.Mode], interval)
+	if degree < 0 {
+		return 0, false
+	}
+	triad := c.triadQuality()
+	diatonic := triad == "" || slices.Contains(diatonicTriads[k.Mode][degree], triad)
+	if seventh := c.seventh(); seventh != 0 {
+		diatonic = diatonic && slices.Contains(k.scaleClasses(), int(c.Root.PitchClass().Add(Semitones(seventh))))
+	}
+	return degree + 1, diatonic
+}
+
+// triadQuality returns "maj", "m", "dim" or "aug" for the triad of the chord
+// tones, or an empty string for chords without a third such as sus chords.
+// A minor third next to a major third is the #9 of a major chord, as in C7#9.
+func (c Chord) triadQuality() string {
+	switch {
+	case c.hasTone(4) && c.hasTone(8) && !c.hasTone(7):
+		return "aug"
+	case c.hasTone(4):
+		return "maj"
+	case c.hasTone(3) && c.hasTone(6) && !c.hasTone(7):
+		return "dim"
+	case c.hasTone(3):
+		return "m"
+	}
+	return ""
+}
+
+// hasTone reports whether a chord tone lies a number of semitones, from 0 to
+// 11, above the root in any octave
+func (c Chord) hasTone(semitones int) bool {
+	return slices.ContainsFunc(c.Tones, func(t int) bool { return mod12(t) == semitones })
+}
@@ -0,0 +1,70 @@
+package godio
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestChordTranspose(t *testing.T) {
+	parameters := []struct {
+		chord     string
+		semitones int
+		spelling  SpellingPolicy
+		expected  string
+	}{
+		{"Bbm7", 2, nil, "Cm7"},
+		{"Bbm7", 1, nil, "Bm7"},
+		{"Bbm7", 3, nil, "Dbm7"},
+		{"C7", 1, nil, "C#7"},
+		{"C7", 1, FlatSpelling, "Db7"},
+		{"Ebmaj7", -1, SharpSpelling, "Dmaj7"},
+		{"C/E", 3, FlatSpelling, "Eb/G"},
+		{"Am7", -14, nil, "Gm7"},
+		{"Eb|C7", 2, nil, "F|D7"},
+	}
+
+	for _, p := range parameters {
+		t.Run(fmt.Sprintf("Testing %v%+d", p.chord, p.semitones), func(t *testing.T) {
+			chord := MustParseChord(p.chord)
+			transposed := chord.Transpose(p.semitones, p.spelling)
+			if transposed.String() != p.expected {
+				t.Errorf("Expected %v, but got %v", p.expected, transposed)
+			}
+			if chord.String() != p.chord {
+				t.Errorf("Expected the original chord to be unchanged, but got %v", chord)
+			}
+		})
+	}
+}
+
+func TestProgressionTranspose(t *testing.T) {
+	parameters := []struct {
+		progression string
+		semitones   int
+		expected    string
+		key         string
+	}{
+		{"Cmaj7 Am7 Dm7 G7", 3, "Ebmaj7 Cm7 Fm7 Bb7", "Eb major"},
+		{"Cmaj7 Am7 Dm7 G7", 4, "Emaj7 C#m7 F#m7 B7", "E major"},
+		{"Ebmaj7 Cm7 Fm7 Bb7", 1, "Emaj7 C#m7 F#m7 B7", "E major"},
+		{"Am Dm E7 Am", 1, "Bbm Ebm F7 Bbm", "Bb minor"},
+		{"Dm7 G7 Cmaj7", -2, "Cm7 F7 Bbmaj7", "Bb major"},
+	}
+
+	for _, p := range parameters {
+		t.Run(fmt.Sprintf("Testing %v%+d", p.progression, p.semitones), func(t *testing.T) {
+			progression := Progression{}
+			for _, symbol := range strings.Fields(p.progression) {
+				progression = append(progression, MustParseChord(symbol))
+			}
+			transposed := progression.Transpose(p.semitones)
+			if transposed.String() != p.expected {
+				t.Errorf("Expected %v, but got %v", p.expected, transposed)
+			}
+			if key := transposed.EstimateKey().String(); key != p.key {
+				t.Errorf("Expected key %v, but got %v", p.key, key)
+			}
+		})
+	}
+}
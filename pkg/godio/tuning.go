@@ -0,0 +1,179 @@
+package godio
+
+import (
+	"fmt"
+	"math"
+	"slices"
+	"strings"
+
+	"github.com/samber/lo"
+)
+
+// Tuning gives the frequency in Hz of MIDI note numbers, where 60 is middle C and 69 is A4
+type Tuning interface {
+	Frequency(note int) float64
+}
+
+// EqualTemperament is twelve-tone equal temperament, in which every semitone has
+// the same frequency ratio, tuned so that A4 sounds at the reference frequency.
+type EqualTemperament struct {
+	Reference float64 // Frequency of A4 in Hz, such as 440, 442 or 415 for baroque pitch
+}
+
+// StandardTuning is equal temperament with A4 at 440 Hz. It is used by chords without a Tuning.
+var StandardTuning = EqualTemperament{Reference: 440}
+
+// Frequency returns the frequency of any MIDI note number, even outside 0 to 127
+func (t EqualTemperament) Frequency(note int) float64 {
+	return t.Reference * math.Exp2(float64(note-69)/12)
+}
+
+// Note returns the MIDI note number closest to a frequency
+func (t EqualTemperament) Note(frequency float64) int {
+	return int(math.Round(69 + 12*math.Log2(frequency/t.Reference)))
+}
+
+// NewNoteFrequencies returns the frequencies of the notes from C0 to B8 in a
+// tuning, keyed by their name with sharps such as "C#4".
+func NewNoteFrequencies(tuning Tuning) NoteFrequenciesMap {
+	frequencies := NoteFrequenciesMap{}
+	for note := 12; note < 120; note++ {
+		frequencies[PitchFromMIDI(note).String()] = tuning.Frequency(note)
+	}
+	return frequencies
+}
+
+// ScaleTuning tunes the keys of a keyboard to a repeating scale, as described by
+// the .scl and .kbm files of the Scala program.
+type ScaleTuning struct {
+	Description string
+	Degrees     []float64 // Cents above the first degree of the scale, the last one being the period, usually 1200 for the octave
+	Mapping     KeyboardMapping
+}
+
+// KeyboardMapping maps MIDI note numbers to the degrees of a ScaleTuning
+type KeyboardMapping struct {
+	Keys               []int   // Degree of each key in a pattern starting at Middle, -1 for unmapped keys. Empty maps each key to the next degree.
+	First              int     // Lowest mapped MIDI note
+	Last               int     // Highest mapped MIDI note
+	Middle             int     // MIDI note of the first degree of the scale
+	ReferenceNote      int     // MIDI note tuned to ReferenceFrequency
+	ReferenceFrequency float64 // Frequency of ReferenceNote in Hz
+	OctaveDegree       int     // Degree reached when the pattern of Keys repeats, 0 for the number of degrees of the scale
+}
+
+// Temperaments are the built-in twelve-tone tunings by name, in cents above the
+// tonic for each semitone from the minor second to the octave.
+var Temperaments = map[string][]float64{
+	"equal": {100, 200, 300, 400, 500, 600, 700, 800, 900, 1000, 1100, 1200},
+	// 5-limit just intonation
+	"just": ratiosToCents(16.0/15, 9.0/8, 6.0/5, 5.0/4, 4.0/3, 45.0/32, 3.0/2, 8.0/5, 5.0/3, 9.0/5, 15.0/8, 2),
+	// Pure fifths from the minor second (Db) to the major seventh (B)
+	"pythagorean": ratiosToCents(256.0/243, 9.0/8, 32.0/27, 81.0/64, 4.0/3, 729.0/512, 3.0/2, 128.0/81, 27.0/16, 16.0/9, 243.0/128, 2),
+	// Fifths narrowed by a quarter of the syntonic comma for pure major thirds, from Eb to G#
+	"meantone":     fifthsToCents(1200*math.Log2(5)/4, -3),
+	"werckmeister": {90.225, 192.180, 294.135, 390.225, 498.045, 588.270, 696.090, 792.180, 888.270, 996.090, 1092.180, 1200},
+	"vallotti":     {94.135, 196.090, 298.045, 392.180, 501.955, 592.180, 698.045, 796.090, 894.135, 1000.000, 1090.225, 1200},
+}
+
+// ratiosToCents converts frequency ratios to cents
+func ratiosToCents(ratios ...float64) []float64 {
+	cents := make([]float64, len(ratios))
+	for i, ratio := range ratios {
+		cents[i] = 1200 * math.Log2(ratio)
+	}
+	return cents
+}
+
+// fifthsToCents returns the twelve-tone scale made of a chain of fifths of a
+// size in cents, starting that many fifths from the tonic.
+func fifthsToCents(fifth float64, start int) []float64 {
+	cents := make([]float64, 12)
+	for i := start; i < start+12; i++ {
+		interval := math.Mod(float64(i)*fifth, 1200)
+		if interval < 0 {
+			interval += 1200
+		}
+		if semitones := mod12(7 * i); semitones > 0 {
+			cents[semitones-1] = interval
+		}
+	}
+	cents[11] = 1200
+	return cents
+}
+
+// NewTemperament returns one of the Temperaments built on a tonic. The tonic
+// sounds as in equal temperament with A4 at the reference frequency.
+func NewTemperament(name string, tonic PitchClass, reference float64) (*ScaleTuning, error) {
+	degrees, ok := Temperaments[name]
+	if !ok {
+		names := lo.Keys(Temperaments)
+		slices.Sort(names)
+		return nil, fmt.Errorf("unknown tuning %q, expected one of %s", name, strings.Join(names, ", "))
+	}
+	return &ScaleTuning{
+		Description: name,
+		Degrees:     degrees,
+		Mapping:     tonicMapping(tonic, reference),
+	}, nil
+}
+
+// tonicMapping maps consecutive keys to consecutive degrees from the tonic in
+// octave 4, which sounds as in equal temperament with A4 at the reference frequency.
+func tonicMapping(tonic PitchClass, reference float64) KeyboardMapping {
+	middle := tonic.Pitch(4).MIDI()
+	return KeyboardMapping{
+		First:              0,
+		Last:               127,
+		Middle:             middle,
+		ReferenceNote:      middle,
+		ReferenceFrequency: EqualTemperament{Reference: reference}.Frequency(middle),
+	}
+}
+
+// Frequency returns the frequency of a MIDI note number, or 0 for keys that are not mapped
+func (t *ScaleTuning) Frequency(note int) float64 {
+	if note < t.Mapping.First || note > t.Mapping.Last {
+		return 0
+	}
+	degree, ok := t.degree(note)
+	if !ok {
+		return 0
+	}
+	reference, _ := t.degree(t.Mapping.ReferenceNote)
+	return t.Mapping.ReferenceFrequency * math.Exp2((t.cents(degree)-t.cents(reference))/1200)
+}
+
+// degree returns the scale degree of a MIDI note number, counted from the first
+// degree at Mapping.Middle, and whether the key is mapped.
+func (t *ScaleTuning) degree(note int) (int, bool) {
+	keys := t.Mapping.Keys
+	if len(keys) == 0 {
+		return note - t.Mapping.Middle, true
+	}
+	repeat := floorDiv(note-t.Mapping.Middle, len(keys))
+	degree := keys[note-t.Mapping.Middle-repeat*len(keys)]
+	if degree < 0 {
+		return 0, false
+	}
+	octave := t.Mapping.OctaveDegree
+	if octave == 0 {
+		octave = len(t.Degrees)
+	}
+	return degree + repeat*octave, true
+}
+
+// cents returns the size in cents of a scale degree above the first degree.
+// Every degree of a scale without degrees is its first degree.
+func (t *ScaleTuning) cents(degree int) float64 {
+	if len(t.Degrees) == 0 {
+		return 0
+	}
+	period := floorDiv(degree, len(t.Degrees))
+	step := degree - period*len(t.Degrees)
+	cents := float64(period) * t.Degrees[len(t.Degrees)-1]
+	if step > 0 {
+		cents += t.Degrees[step-1]
+	}
+	return cents
+}
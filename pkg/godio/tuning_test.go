@@ -0,0 +1,204 @@
+package godio
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestEqualTemperament(t *testing.T) {
+	parameters := []struct {
+		reference float64
+		pitch     string
+		expected  float64
+	}{
+		{440, "A4", 440},
+		{440, "A5", 880},
+		{440, "C4", 261.6255653005986},
+		{440, "C0", 16.351597831287414},
+		{440, "C-1", 8.175798915643707},
+		{415, "A4", 415},
+		{415, "A3", 207.5},
+		{432, "E5", 647.2686572107264},
+		{442, "A#4", 468.28268770680853},
+	}
+
+	for _, p := range parameters {
+		t.Run(fmt.Sprintf("Testing %v at A=%v", p.pitch, p.reference), func(t *testing.T) {
+			tuning := EqualTemperament{Reference: p.reference}
+			frequency := tuning.Frequency(ParsePitch(p.pitch).MIDI())
+			if math.Abs(frequency-p.expected) > 1e-9 {
+				t.Errorf("Expected %v, but got %v", p.expected, frequency)
+			}
+			if note := tuning.Note(frequency); note != ParsePitch(p.pitch).MIDI() {
+				t.Errorf("Expected MIDI note %d, but got %d", ParsePitch(p.pitch).MIDI(), note)
+			}
+		})
+	}
+}
+
+func TestChordTuning(t *testing.T) {
+	chord := MustParseChord("Am")
+	standard := chord.GetFrequencies()
+	chord.Tuning = EqualTemperament{Reference: 415}
+	baroque := chord.GetFrequencies()
+	for i := range standard {
+		if ratio := baroque[i] / standard[i]; math.Abs(ratio-415.0/440) > 1e-12 {
+			t.Errorf("Expected every frequency to be scaled by 415/440, but got %v for %v", ratio, standard[i])
+		}
+	}
+	if baroque[0] != 103.75 {
+		t.Errorf("Expected A2 at 103.75 Hz, but got %v", baroque[0])
+	}
+
+	if frequencies := chord.GetFrequenciesV2(); frequencies[len(frequencies)-1] != 103.75 {
+		t.Errorf("Expected A2 at 103.75 Hz with voicing v2, but got %v", frequencies[len(frequencies)-1])
+	}
+}
+
+func TestTemperaments(t *testing.T) {
+	parameters := []struct {
+		name     string
+		tonic    string
+		lower    string
+		upper    string
+		expected float64 // Frequency ratio of upper to lower
+	}{
+		{"equal", "C", "C4", "G4", math.Exp2(7.0 / 12)},
+		{"just", "C", "C4", "E4", 5.0 / 4},
+		{"just", "C", "C4", "G4", 3.0 / 2},
+		{"just", "D", "D4", "F#4", 5.0 / 4},
+		{"just", "D", "C4", "D4", 10.0 / 9},
+		{"pythagorean", "C", "C4", "E4", 81.0 / 64},
+		{"pythagorean", "C", "F4", "C5", 3.0 / 2},
+		{"meantone", "C", "C4", "E4", 5.0 / 4},
+		{"meantone", "C", "Eb4", "G4", 5.0 / 4},
+		{"meantone", "C", "C4", "C5", 2},
+		{"werckmeister", "C", "C4", "G4", math.Exp2(696.090 / 1200)},
+		{"vallotti", "C", "F4", "A4", math.Exp2((894.135 - 501.955) / 1200)},
+	}
+
+	for _, p := range parameters {
+		t.Run(fmt.Sprintf("Testing %v in %v %v", p.upper, p.name, p.tonic), func(t *testing.T) {
+			tuning, err := NewTemperament(p.name, ParseNote(p.tonic).PitchClass(), 440)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			ratio := tuning.Frequency(ParsePitch(p.upper).MIDI()) / tuning.Frequency(ParsePitch(p.lower).MIDI())
+			if math.Abs(ratio-p.expected) > 1e-9 {
+				t.Errorf("Expected a ratio of %v, but got %v", p.expected, ratio)
+			}
+			tonic := ParseNote(p.tonic).Pitch(4).MIDI()
+			if math.Abs(tuning.Frequency(tonic)-StandardTuning.Frequency(tonic)) > 1e-9 {
+				t.Errorf("Expected the tonic at %v, but got %v", StandardTuning.Frequency(tonic), tuning.Frequency(tonic))
+			}
+		})
+	}
+
+	if _, err := NewTemperament("kirnberger", 0, 440); err == nil {
+		t.Errorf("Expected an error for an unknown tuning")
+	}
+}
+
+func TestScala(t *testing.T) {
+	scl := `! pentatonic.scl
+!
+Just pentatonic scale
+ 5
+!
+ 9/8
+ 5/4
+ 701.955 cents
+ 5/3
+ 2/1
+`
+	kbm := `! Pentatonic on the white keys starting at C4, with A4 at 440 Hz
+12
+0
+127
+60
+69
+440.0
+5
+! Mapping
+0
+x
+1
+x
+2
+x
+x
+3
+x
+4
+x
+x
+`
+	tuning, err := ParseScala(strings.NewReader(scl))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if tuning.Description != "Just pentatonic scale" || len(tuning.Degrees) != 5 {
+		t.Fatalf("Expected the just pentatonic scale with 5 degrees, but got %+v", tuning)
+	}
+	tuning.Mapping, err = ParseKeyboardMapping(strings.NewReader(kbm))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	parameters := []struct {
+		pitch    string
+		expected float64
+	}{
+		{"A4", 440},
+		{"C4", 440 * 3.0 / 5},
+		{"D4", 440 * 3.0 / 5 * 9.0 / 8},
+		{"G4", 440 * 3.0 / 5 * math.Exp2(701.955/1200)},
+		{"C5", 440 * 6.0 / 5},
+		{"A3", 220},
+		{"F4", 0},
+		{"B4", 0},
+	}
+
+	for _, p := range parameters {
+		t.Run(fmt.Sprintf("Testing %v", p.pitch), func(t *testing.T) {
+			frequency := tuning.Frequency(ParsePitch(p.pitch).MIDI())
+			if math.Abs(frequency-p.expected) > 1e-9 {
+				t.Errorf("Expected %v, but got %v", p.expected, frequency)
+			}
+		})
+	}
+
+	t.Run("Testing octave degree 0", func(t *testing.T) {
+		mapping, err := ParseKeyboardMapping(strings.NewReader(strings.Replace(kbm, "\n5\n", "\n0\n", 1)))
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		scale := &ScaleTuning{Degrees: tuning.Degrees, Mapping: mapping}
+		for _, pitch := range []string{"C4", "C5", "A3"} {
+			if frequency, expected := scale.Frequency(ParsePitch(pitch).MIDI()), tuning.Frequency(ParsePitch(pitch).MIDI()); math.Abs(frequency-expected) > 1e-9 {
+				t.Errorf("Expected %v for %v, but got %v", expected, pitch, frequency)
+			}
+		}
+	})
+
+	t.Run("Testing 0 notes", func(t *testing.T) {
+		unison, err := ParseScala(strings.NewReader("Unison\n0\n"))
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		unison.Mapping = tonicMapping(ParseNote("A").PitchClass(), 440)
+		for _, pitch := range []string{"C4", "A4", "E5"} {
+			if frequency := unison.Frequency(ParsePitch(pitch).MIDI()); math.Abs(frequency-440) > 1e-9 {
+				t.Errorf("Expected 440 for %v, but got %v", pitch, frequency)
+			}
+		}
+	})
+
+	for _, invalid := range []string{"", "Scale\n-1\n", "Scale\n2\n3/2\n", "Scale\n1\n0/2\n", "Scale\n1\nabc\n"} {
+		if _, err := ParseScala(strings.NewReader(invalid)); err == nil {
+			t.Errorf("Expected an error for %q", invalid)
+		}
+	}
+}
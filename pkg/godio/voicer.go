@@ -0,0 +1,210 @@
+package godio
+
+import (
+	"math"
+	"slices"
+
+	"github.com/samber/lo"
+)
+
+// Voicing is the MIDI note numbers of a voiced chord, the bass first and the upper voices in ascending order
+type Voicing []int
+
+// Frequencies returns the frequencies of the notes of the voicing in a tuning
+func (v Voicing) Frequencies(tuning Tuning) []float64 {
+	return lo.Map(v, func(note int, _ int) float64 {
+		return tuning.Frequency(note)
+	})
+}
+
+// Voicer voices a progression of chords. The upper voices of each chord are placed
+// in the octaves that move the least from the previous chord, while the bass note
+// is voiced on its own below them.
+type Voicer struct {
+	Low         int                // Lowest MIDI note of the upper voices
+	High        int                // Highest MIDI note of the upper voices
+	MaxSpacing  int                // Largest interval in semitones between adjacent upper voices, 0 for no limit
+	CommonTones float64            // Cost, in semitones of movement, of not holding a tone shared with the previous chord
+	BassLow     int                // Lowest MIDI note of the bass, which is voiced in the octave above it
+	Style       VoicingStyle       // When set, each chord keeps the shape of Chord.Voice and only its octave is chosen
+	Constraints VoicingConstraints // Limits of every voicing, replaced by the constraints of a chord when it has some
+}
+
+// NewVoicer returns a Voicer keeping the upper voices between G3 and E5, no more
+// than an octave apart, and the bass between E2 and D#3.
+func NewVoicer() *Voicer {
+	return &Voicer{
+		Low:         ParsePitch("G3").MIDI(),
+		High:        ParsePitch("E5").MIDI(),
+		MaxSpacing:  12,
+		CommonTones: 2,
+		BassLow:     ParsePitch("E2").MIDI(),
+	}
+}
+
+// Voice returns the voicing of each chord of a progression
+func (v *Voicer) Voice(chords []*Chord) []Voicing {
+	voicings := make([]Voicing, 0, len(chords))
+	var previous Voicing
+	for _, chord := range chords {
+		voicing := v.VoiceNext(previous, chord)
+		voicings = append(voicings, voicing)
+		previous = voicing
+	}
+	return voicings
+}
+
+// VoiceNext returns the voicing of a chord that follows the previous voicing,
+// which may be nil for the first chord of a progression.
+func (v *Voicer) VoiceNext(previous Voicing, chord *Chord) Voicing {
+	bass := v.BassLow + mod12(int(chord.bass().PitchClass())-v.BassLow)
+	var candidates [][]int
+	if v.Style != "" {
+		candidates = v.styleCandidates(chord.Voice(v.Style)[1:])
+	} else {
+		candidates = v.candidates(chord.upperPitchClasses())
+	}
+
+	constraints := v.Constraints
+	if !chord.Constraints.IsZero() {
+		constraints = chord.Constraints
+	}
+	allowed := lo.Filter(candidates, func(candidate []int, _ int) bool {
+		return constraints.Allows(append([]int{bass}, candidate...))
+	})
+	if len(allowed) > 0 {
+		candidates = allowed
+	}
+
+	best, bestCost := []int{}, math.Inf(1)
+	for _, candidate := range candidates {
+		var cost float64
+		if len(previous) > 1 {
+			cost = v.movement(previous[1:], candidate)
+		} else {
+			cost = v.placement(candidate)
+		}
+		if constraints.TargetTopNote > 0 && len(candidate) > 0 {
+			cost += math.Abs(float64(candidate[len(candidate)-1] - constraints.TargetTopNote))
+		}
+		if cost < bestCost {
+			best, bestCost = candidate, cost
+		}
+	}
+	voicing := append(Voicing{bass}, best...)
+	if len(allowed) == 0 && !constraints.IsZero() {
+		return constraints.Apply(voicing)
+	}
+	return voicing
+}
+
+// upperPitchClasses returns the pitch classes of the chord tones once the voicing rules are applied
+func (c Chord) upperPitchClasses() []int {
+	c.Tones = slices.Clone(c.Tones)
+	c.applyVoicingRules()
+	return lo.Uniq(lo.Map(c.Tones, func(tone int, _ int) int {
+		return int(c.Root.PitchClass().Add(Semitones(tone)))
+	}))
+}
+
+// VoicedPitchClasses returns the sorted pitch classes of the bass note and of the
+// chord tones that remain once the voicing rules are applied
+func (c Chord) VoicedPitchClasses() []int {
+	classes := append(c.upperPitchClasses(), int(c.bass().PitchClass()))
+	classes = lo.Uniq(classes)
+	slices.Sort(classes)
+	return classes
+}
+
+// candidates returns the ascending sets of notes within range made of one note of
+// each pitch class. When none respects MaxSpacing, the spacing is not enforced.
+func (v *Voicer) candidates(pitchClasses []int) [][]int {
+	all := [][]int{{}}
+	for _, pitchClass := range pitchClasses {
+		extended := [][]int{}
+		for note := v.Low + mod12(pitchClass-v.Low); note <= v.High; note += 12 {
+			for _, notes := range all {
+				extended = append(extended, append(slices.Clone(notes), note))
+			}
+		}
+		if len(extended) > 0 {
+			all = extended
+		}
+	}
+	for _, notes := range all {
+		slices.Sort(notes)
+	}
+
+	spaced := lo.Filter(all, func(notes []int, _ int) bool {
+		for i := 1; i < len(notes); i++ {
+			if v.MaxSpacing > 0 && notes[i]-notes[i-1] > v.MaxSpacing {
+				return false
+			}
+		}
+		return true
+	})
+	if len(spaced) == 0 {
+		return all
+	}
+	return spaced
+}
+
+// styleCandidates returns the shape of a styled voicing moved by octaves within
+// range. When it does not fit in range, the shape is kept where it is.
+func (v *Voicer) styleCandidates(shape []int) [][]int {
+	candidates := [][]int{}
+	if len(shape) == 0 {
+		return [][]int{shape}
+	}
+	for octave := -3; octave <= 3; octave++ {
+		moved := lo.Map(shape, func(note int, _ int) int { return note + 12*octave })
+		if moved[0] >= v.Low && moved[len(moved)-1] <= v.High {
+			candidates = append(candidates, moved)
+		}
+	}
+	if len(candidates) == 0 {
+		return [][]int{shape}
+	}
+	return candidates
+}
+
+// movement is the cost of moving from the previous upper voices to the next ones:
+// the distance from each note to the closest note of the other chord, plus the
+// cost of the common tones that are not held.
+func (v *Voicer) movement(previous []int, next []int) float64 {
+	cost := 0.0
+	for _, note := range next {
+		cost += float64(closestDistance(previous, note))
+	}
+	for _, note := range previous {
+		cost += float64(closestDistance(next, note))
+		shared := slices.ContainsFunc(next, func(n int) bool { return mod12(n) == mod12(note) })
+		if shared && !slices.Contains(next, note) {
+			cost += v.CommonTones
+		}
+	}
+	return cost
+}
+
+// placement is the cost of the voicing of the first chord, which is kept compact
+// and centered in the range of the upper voices.
+func (v *Voicer) placement(notes []int) float64 {
+	if len(notes) == 0 {
+		return 0
+	}
+	center := float64(v.Low+v.High) / 2
+	mean := float64(lo.Sum(notes)) / float64(len(notes))
+	return float64(notes[len(notes)-1]-notes[0]) + math.Abs(mean-center)
+}
+
+// closestDistance returns the distance in semitones from note to the closest of notes
+func closestDistance(notes []int, note int) int {
+	distance := math.MaxInt
+	for _, n := range notes {
+		distance = min(distance, max(n-note, note-n))
+	}
+	if distance == math.MaxInt {
+		return 0
+	}
+	return distance
+}
@@ -0,0 +1,68 @@
+package godio
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/samber/lo"
+)
+
+func TestVoicer(t *testing.T) {
+	progressions := []string{
+		"Dm7 G7 Cmaj7",
+		"C Am F G7 C",
+		"Cmaj7 A7 Dm9 G13 Cmaj9",
+		"Bbm7 Eb7 Abmaj7 Db7#11 Gø7 C7b9 Fm6",
+	}
+
+	for _, progression := range progressions {
+		t.Run(fmt.Sprintf("Testing %v", progression), func(t *testing.T) {
+			chords := lo.Map(strings.Fields(progression), func(symbol string, _ int) *Chord {
+				return MustParseChord(symbol)
+			})
+			voicer := NewVoicer()
+			voicings := voicer.Voice(chords)
+
+			for i, voicing := range voicings {
+				if mod12(voicing[0]) != int(chords[i].bass().PitchClass()) || voicing[0] < voicer.BassLow || voicing[0] >= voicer.BassLow+12 {
+					t.Errorf("Expected the bass of %v in range, but got %v", chords[i], voicing)
+				}
+				upper := voicing[1:]
+				if !slices.IsSorted(upper) || upper[0] < voicer.Low || upper[len(upper)-1] > voicer.High {
+					t.Errorf("Expected the upper voices of %v sorted and in range, but got %v", chords[i], voicing)
+				}
+				for j := 1; j < len(upper); j++ {
+					if upper[j]-upper[j-1] > voicer.MaxSpacing {
+						t.Errorf("Expected the upper voices of %v within %d semitones, but got %v", chords[i], voicer.MaxSpacing, voicing)
+					}
+				}
+				classes := lo.Uniq(lo.Map(upper, func(note int, _ int) int { return mod12(note) }))
+				if !lo.Every(chords[i].PitchClasses(), classes) {
+					t.Errorf("Expected the upper voices of %v to be chord tones, but got %v", chords[i], voicing)
+				}
+
+				if i > 0 {
+					// Voice leading keeps every voice within a fourth of the previous chord
+					for _, note := range upper {
+						if distance := closestDistance(voicings[i-1][1:], note); distance > 5 {
+							t.Errorf("Expected smooth voice leading from %v to %v, but got %v to %v", chords[i-1], chords[i], voicings[i-1], voicing)
+						}
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestVoicerCommonTones(t *testing.T) {
+	voicer := NewVoicer()
+	voicings := voicer.Voice([]*Chord{MustParseChord("C"), MustParseChord("Am"), MustParseChord("F")})
+	for i := 1; i < len(voicings); i++ {
+		common := lo.Intersect(voicings[i-1][1:], voicings[i][1:])
+		if len(common) != 2 {
+			t.Errorf("Expected two common tones held from %v to %v", voicings[i-1], voicings[i])
+		}
+	}
+}
@@ -1,6 +1,19 @@
 package godio
 
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+
+	"github.com/samber/lo"
+)
+
+// VoicingRule removes or replaces chord tones when its condition holds
 type VoicingRule struct {
+	Name      string
+	Priority  int // Rules with a higher priority are applied first, rules of equal priority in order
 	Condition func(*Chord) bool
 	Action    func(*Chord)
 }
@@ -9,58 +22,286 @@ type VoicingRule struct {
 var defaultVoicingRules = []VoicingRule{
 	{
 		// In ninth chords, fifth is often omitted
+		Name:     "omit-fifth-in-ninths",
+		Priority: 60,
 		Condition: func(c *Chord) bool {
-			return c.hasInterval(2) || c.hasInterval(1) || c.hasInterval(14) || c.hasInterval(13)
+			return c.hasInterval(MajorSecond) || c.hasInterval(MinorSecond) || c.hasInterval(MajorNinth) || c.hasInterval(MinorNinth)
 		},
 		Action: func(c *Chord) {
-			c.removeTone(7)
+			c.removeTone(PerfectFifth.Semitones)
 		},
 	},
 	{
 		// In thirteenth chords, fifth and ninth are often omitted
+		Name:     "omit-fifth-and-ninth-in-thirteenths",
+		Priority: 50,
 		Condition: func(c *Chord) bool {
-			return c.hasInterval(9) || c.hasInterval(8) || c.hasInterval(21) || c.hasInterval(20)
+			return c.hasInterval(MajorSixth) || c.hasInterval(MinorSixth) || c.hasInterval(MajorThirteenth) || c.hasInterval(MinorThirteenth)
 		},
 		Action: func(c *Chord) {
-			c.removeTone(7)
-			c.removeTone(14)
+			c.removeTone(PerfectFifth.Semitones)
+			c.removeTone(MajorNinth.Semitones)
 		},
 	},
 	{
 		// In altered dominant chords, natural fifth is omitted
+		Name:     "omit-fifth-in-altered",
+		Priority: 40,
 		Condition: func(c *Chord) bool {
-			return (c.hasInterval(6) || c.hasInterval(8)) && !c.hasInterval(11)
+			return (c.hasInterval(DiminishedFifth) || c.hasInterval(AugmentedFifth)) && !c.hasInterval(MajorSeventh)
 		},
 		Action: func(c *Chord) {
-			c.removeTone(7)
+			c.removeTone(PerfectFifth.Semitones)
 		},
 	},
 	{
 		// In eleventh chords, third is often omitted (except in maj11)
+		Name:     "omit-third-in-elevenths",
+		Priority: 30,
 		Condition: func(c *Chord) bool {
-			return c.hasInterval(17) || c.hasInterval(5) && !c.hasInterval(11)
+			return c.hasInterval(PerfectEleventh) || c.hasInterval(PerfectFourth) && !c.hasInterval(MajorSeventh)
 		},
 		Action: func(c *Chord) {
-			c.removeTone(4)
-			c.removeTone(3)
+			c.removeTone(MajorThird.Semitones)
+			c.removeTone(MinorThird.Semitones)
 		},
 	},
 	{
 		// Remove upper root if there are 5 or more tones
+		Name:     "omit-upper-root",
+		Priority: 20,
 		Condition: func(c *Chord) bool {
 			return len(c.Tones) >= 5
 		},
 		Action: func(c *Chord) {
-			c.removeTone(0)
+			c.removeTone(PerfectUnison.Semitones)
 		},
 	},
 	{
 		// Remove the fifth if chord has more than 5 notes and no alteration of the fifth
+		Name:     "omit-fifth-in-large-chords",
+		Priority: 10,
 		Condition: func(c *Chord) bool {
-			return len(c.Tones) >= 5 && !c.hasInterval(8) && !c.hasInterval(6)
+			return len(c.Tones) >= 5 && !c.hasInterval(AugmentedFifth) && !c.hasInterval(DiminishedFifth)
 		},
 		Action: func(c *Chord) {
-			c.removeTone(7)
+			c.removeTone(PerfectFifth.Semitones)
 		},
 	},
 }
+
+// VoicingRuleSet is a named set of voicing rules applied by decreasing priority
+type VoicingRuleSet struct {
+	Name  string
+	Rules []VoicingRule
+}
+
+// VoicingStep records the tones removed and added by a voicing rule, in semitones above the root
+type VoicingStep struct {
+	Rule    string
+	Removed []int
+	Added   []int
+}
+
+// voicingRules are the rules registered with RegisterVoicingRule, by name
+var voicingRules = lo.KeyBy(defaultVoicingRules, func(rule VoicingRule) string {
+	return rule.Name
+})
+
+// voicingRuleSets are the rule sets registered with RegisterVoicingRuleSet, by name
+var voicingRuleSets = map[string]*VoicingRuleSet{
+	"default": NewVoicingRuleSet("default", defaultVoicingRules...),
+	"none":    NewVoicingRuleSet("none"),
+}
+
+// NewVoicingRuleSet returns a rule set with the rules sorted by decreasing priority
+func NewVoicingRuleSet(name string, rules ...VoicingRule) *VoicingRuleSet {
+	sorted := slices.Clone(rules)
+	slices.SortStableFunc(sorted, func(a, b VoicingRule) int {
+		return b.Priority - a.Priority
+	})
+	return &VoicingRuleSet{Name: name, Rules: sorted}
+}
+
+// With returns a copy of the rule set with more rules. Rules replace the rules of the same name.
+func (s *VoicingRuleSet) With(rules ...VoicingRule) *VoicingRuleSet {
+	names := lo.Map(rules, func(rule VoicingRule, _ int) string { return rule.Name })
+	return NewVoicingRuleSet(s.Name, append(s.Without(names...).Rules, rules...)...)
+}
+
+// Without returns a copy of the rule set without the rules of the given names
+func (s *VoicingRuleSet) Without(names ...string) *VoicingRuleSet {
+	return &VoicingRuleSet{Name: s.Name, Rules: lo.Filter(s.Rules, func(rule VoicingRule, _ int) bool {
+		return rule.Name == "" || !slices.Contains(names, rule.Name)
+	})}
+}
+
+// Apply applies the rules to a chord and returns the steps of the rules that changed its tones
+func (s *VoicingRuleSet) Apply(c *Chord) []VoicingStep {
+	return applyVoicingRules(c, s.Rules)
+}
+
+// RegisterVoicingRule makes a rule available by name to the rule sets loaded by
+// LoadVoicingRuleSet. It panics if the rule has no name or the name is taken.
+func RegisterVoicingRule(rule VoicingRule) {
+	if rule.Name == "" {
+		panic("godio: voicing rule without a name")
+	}
+	if _, ok := voicingRules[rule.Name]; ok {
+		panic(fmt.Sprintf("godio: voicing rule %q registered twice", rule.Name))
+	}
+	voicingRules[rule.Name] = rule
+}
+
+// RegisterVoicingRuleSet makes a rule set available by name to
+// VoicingRuleSetByName. A rule set of the same name is replaced.
+func RegisterVoicingRuleSet(set *VoicingRuleSet) {
+	voicingRuleSets[set.Name] = set
+}
+
+// VoicingRuleSetByName returns a registered rule set, such as "default" or "none"
+func VoicingRuleSetByName(name string) (*VoicingRuleSet, error) {
+	set, ok := voicingRuleSets[name]
+	if !ok {
+		names := lo.Keys(voicingRuleSets)
+		slices.Sort(names)
+		return nil, fmt.Errorf("unknown voicing rule set %q, expected one of %s", name, strings.Join(names, ", "))
+	}
+	return set, nil
+}
+
+// voicingRuleSetJSON is the JSON representation of a rule set read by LoadVoicingRuleSet
+type voicingRuleSetJSON struct {
+	Name    string            `json:"name"`
+	Extends string            `json:"extends"`
+	Disable []string          `json:"disable"`
+	Rules   []voicingRuleJSON `json:"rules"`
+}
+
+type voicingRuleJSON struct {
+	Name     string `json:"name"`
+	Priority *int   `json:"priority"`
+	When     *struct {
+		Any      []int `json:"any"`
+		None     []int `json:"none"`
+		MinTones int   `json:"minTones"`
+	} `json:"when"`
+	Remove []int `json:"remove"`
+}
+
+// LoadVoicingRuleSet reads a rule set from JSON such as
+//
+//	{
+//	  "name": "sparse",
+//	  "extends": "default",
+//	  "disable": ["omit-upper-root"],
+//	  "rules": [
+//	    {"name": "omit-fifth-in-ninths", "priority": 100},
+//	    {"name": "omit-root-in-sevenths", "when": {"any": [10, 11], "minTones": 4}, "remove": [0]}
+//	  ]
+//	}
+//
+// The rule set starts from the registered rule set it extends, if any, without
+// the disabled rules. A rule with a "remove" list removes these tones, given in
+// semitones above the root, when the chord has any of the "any" tones, none of
+// the "none" tones and at least "minTones" tones. A rule without one refers to a
+// registered rule, whose priority may be changed. The rule set is not registered.
+func LoadVoicingRuleSet(r io.Reader) (*VoicingRuleSet, error) {
+	var data voicingRuleSetJSON
+	if err := json.NewDecoder(r).Decode(&data); err != nil {
+		return nil, fmt.Errorf("invalid voicing rule set: %w", err)
+	}
+
+	set := NewVoicingRuleSet(data.Name)
+	if data.Extends != "" {
+		base, err := VoicingRuleSetByName(data.Extends)
+		if err != nil {
+			return nil, fmt.Errorf("invalid voicing rule set %q: %w", data.Name, err)
+		}
+		set = NewVoicingRuleSet(data.Name, base.Rules...)
+	}
+	set = set.Without(data.Disable...)
+
+	for _, ruleData := range data.Rules {
+		rule, err := ruleData.rule()
+		if err != nil {
+			return nil, fmt.Errorf("invalid voicing rule set %q: %w", data.Name, err)
+		}
+		set = set.With(rule)
+	}
+	return set, nil
+}
+
+// rule builds the rule described by the JSON data
+func (r voicingRuleJSON) rule() (VoicingRule, error) {
+	if r.Remove == nil {
+		rule, ok := voicingRules[r.Name]
+		if !ok {
+			return VoicingRule{}, fmt.Errorf("unknown voicing rule %q", r.Name)
+		}
+		if r.Priority != nil {
+			rule.Priority = *r.Priority
+		}
+		return rule, nil
+	}
+
+	rule := VoicingRule{
+		Name:      r.Name,
+		Condition: func(*Chord) bool { return true },
+		Action: func(c *Chord) {
+			for _, tone := range r.Remove {
+				c.removeTone(tone)
+			}
+		},
+	}
+	if r.Priority != nil {
+		rule.Priority = *r.Priority
+	}
+	if r.When != nil {
+		when := *r.When
+		rule.Condition = func(c *Chord) bool {
+			return (len(when.Any) == 0 || lo.Some(c.Tones, when.Any)) && !lo.Some(c.Tones, when.None) && len(c.Tones) >= when.MinTones
+		}
+	}
+	return rule, nil
+}
+
+// applyVoicingRules applies rules by decreasing priority and records the steps
+// of the rules that changed the chord tones.
+func applyVoicingRules(c *Chord, rules []VoicingRule) []VoicingStep {
+	sorted := NewVoicingRuleSet("", rules...).Rules
+	steps := []VoicingStep{}
+	for _, rule := range sorted {
+		if !rule.Condition(c) {
+			continue
+		}
+		before := slices.Clone(c.Tones)
+		rule.Action(c)
+		c.keepExplicitTones(before)
+		removed, added := lo.Difference(before, c.Tones)
+		if len(removed) > 0 || len(added) > 0 {
+			steps = append(steps, VoicingStep{Rule: rule.Name, Removed: removed, Added: added})
+		}
+	}
+	return steps
+}
+
+// keepExplicitTones undoes what a rule changed against the chord symbol: the
+// tones of extensions it removed are put back, and the tones of omitted degrees
+// it added are removed again
+func (c *Chord) keepExplicitTones(before []int) {
+	for _, extension := range c.Extensions {
+		for _, tone := range extensionTones(extension) {
+			if slices.Contains(before, tone) && !slices.Contains(c.Tones, tone) {
+				c.addTone(tone)
+			}
+		}
+	}
+	for _, omission := range c.Omissions {
+		for _, tone := range omissionFormulas[omission] {
+			if !slices.Contains(before, tone) {
+				c.removeTone(tone)
+			}
+		}
+	}
+}
@@ -0,0 +1,135 @@
+package godio
+
+import (
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestTraceVoicingRules(t *testing.T) {
+	parameters := []struct {
+		chord string
+		steps []VoicingStep
+	}{
+		{"Cmaj7", []VoicingStep{}},
+		{"C9", []VoicingStep{{Rule: "omit-fifth-in-ninths", Removed: []int{7}, Added: []int{}}}},
+		{"C7b9", []VoicingStep{{Rule: "omit-upper-root", Removed: []int{0}, Added: []int{}}}},
+	}
+
+	for _, p := range parameters {
+		t.Run(fmt.Sprintf("Testing %v", p.chord), func(t *testing.T) {
+			chord := MustParseChord(p.chord)
+			tones := slices.Clone(chord.Tones)
+			steps := chord.TraceVoicingRules()
+			if !reflect.DeepEqual(steps, p.steps) {
+				t.Errorf("Expected %v, but got %v", p.steps, steps)
+			}
+			if !slices.Equal(chord.Tones, tones) {
+				t.Errorf("Expected the tones %v to be kept, but got %v", tones, chord.Tones)
+			}
+		})
+	}
+}
+
+func TestVoicingRuleSet(t *testing.T) {
+	set, err := VoicingRuleSetByName("default")
+	if err != nil {
+		t.Fatalf("Expected the default rule set, but got %v", err)
+	}
+	if !slices.IsSortedFunc(set.Rules, func(a, b VoicingRule) int { return b.Priority - a.Priority }) {
+		t.Errorf("Expected the rules sorted by priority, but got %v", set.Rules)
+	}
+
+	t.Run("Testing Without", func(t *testing.T) {
+		chord := MustParseChord("C9").WithVoicingRules(set.Without("omit-fifth-in-ninths"))
+		chord.applyVoicingRules()
+		if !slices.Contains(chord.Tones, 7) {
+			t.Errorf("Expected the fifth to be kept, but got %v", chord.Tones)
+		}
+	})
+
+	t.Run("Testing priorities", func(t *testing.T) {
+		// Rules run by priority, so remove-root runs first and add-missing-root then restores the root
+		addRoot := VoicingRule{
+			Name:      "add-missing-root",
+			Condition: func(c *Chord) bool { return !slices.Contains(c.Tones, 0) },
+			Action:    func(c *Chord) { c.Tones = append(c.Tones, 0) },
+		}
+		removeRoot := VoicingRule{
+			Name:      "remove-root",
+			Priority:  1,
+			Condition: func(c *Chord) bool { return true },
+			Action:    func(c *Chord) { c.removeTone(0) },
+		}
+		chord := MustParseChord("C").WithVoicingRules(NewVoicingRuleSet("test", addRoot, removeRoot))
+		chord.applyVoicingRules()
+		if !slices.Contains(chord.Tones, 0) {
+			t.Errorf("Expected the root to be added back, but got %v", chord.Tones)
+		}
+	})
+
+	t.Run("Testing explicit tones", func(t *testing.T) {
+		// Rules cannot remove added tones or bring back omitted ones
+		rewrite := VoicingRule{
+			Name:      "rewrite",
+			Condition: func(c *Chord) bool { return true },
+			Action: func(c *Chord) {
+				c.removeTone(2)
+				c.removeTone(5)
+				c.addTone(7)
+			},
+		}
+		chord := MustParseChord("Cmadd2add4(no5)").WithVoicingRules(NewVoicingRuleSet("test", rewrite))
+		chord.applyVoicingRules()
+		if !slices.Equal(chord.Tones, []int{0, 3, 2, 5}) {
+			t.Errorf("Expected [0 3 2 5], but got %v", chord.Tones)
+		}
+	})
+
+	t.Run("Testing unknown rule set", func(t *testing.T) {
+		if _, err := VoicingRuleSetByName("unknown"); err == nil {
+			t.Errorf("Expected an error, but got nil")
+		}
+	})
+}
+
+func TestLoadVoicingRuleSet(t *testing.T) {
+	RegisterVoicingRule(VoicingRule{
+		Name:      "test-omit-third",
+		Condition: func(c *Chord) bool { return true },
+		Action:    func(c *Chord) { c.removeTone(4) },
+	})
+	t.Cleanup(func() { delete(voicingRules, "test-omit-third") })
+
+	set, err := LoadVoicingRuleSet(strings.NewReader(`{
+		"name": "sparse",
+		"extends": "default",
+		"disable": ["omit-fifth-in-ninths"],
+		"rules": [
+			{"name": "test-omit-third", "priority": 100},
+			{"name": "omit-root-in-sevenths", "when": {"any": [10, 11], "minTones": 4}, "remove": [0]}
+		]
+	}`))
+	if err != nil {
+		t.Fatalf("Expected a rule set, but got %v", err)
+	}
+
+	chord := MustParseChord("C9").WithVoicingRules(set)
+	steps := chord.TraceVoicingRules()
+	rules := make([]string, len(steps))
+	for i, step := range steps {
+		rules[i] = step.Rule
+	}
+	expected := []string{"test-omit-third", "omit-root-in-sevenths"}
+	if !slices.Equal(rules, expected) {
+		t.Errorf("Expected the rules %v, but got %v", expected, rules)
+	}
+
+	for _, input := range []string{`{"rules": [{"name": "unknown"}]}`, `{"extends": "unknown"}`, `{"rules": `} {
+		if _, err := LoadVoicingRuleSet(strings.NewReader(input)); err == nil {
+			t.Errorf("Expected an error for %v, but got nil", input)
+		}
+	}
+}